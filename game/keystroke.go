@@ -0,0 +1,68 @@
+package game
+
+import (
+	"time"
+
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/quotes"
+)
+
+// PressKey applies a key that typed text to the typed input and returns the
+// new input with the keystroke to log
+func PressKey(prompt, typed, text string) (string, scoring.Keystroke) {
+	position := quotes.GraphemeCount(typed)
+	next := TypeText(prompt, typed, text)
+	return next, scoring.Keystroke{
+		Key:      text,
+		At:       time.Now(),
+		Position: position,
+		Correct:  matchesFrom(prompt, next, position),
+	}
+}
+
+// PressBackspace applies a backspace to the typed input and returns the new
+// input with the keystroke to log, or false if there was nothing to delete
+func PressBackspace(prompt, typed string) (string, scoring.Keystroke, bool) {
+	next := DeleteText(prompt, typed)
+	if next == typed {
+		return typed, scoring.Keystroke{}, false
+	}
+	return next, scoring.Keystroke{
+		Backspace: true,
+		At:        time.Now(),
+		Position:  quotes.GraphemeCount(typed),
+		Correct:   matchesFrom(prompt, typed, quotes.GraphemeCount(next)),
+	}, true
+}
+
+// Type applies a key that typed text to the player's input and logs it
+func (p *Player) Type(text, prompt string) {
+	typed, key := PressKey(prompt, p.TypedInput, text)
+	p.Keystrokes = append(p.Keystrokes, key)
+	p.UpdateProgress(typed, prompt)
+}
+
+// Backspace applies a backspace to the player's input and logs it
+func (p *Player) Backspace(prompt string) {
+	typed, key, ok := PressBackspace(prompt, p.TypedInput)
+	if !ok {
+		return
+	}
+	p.Keystrokes = append(p.Keystrokes, key)
+	p.UpdateProgress(typed, prompt)
+}
+
+// matchesFrom reports whether the typed grapheme clusters from position on
+// match the prompt. A mark that joined the cluster before position is
+// judged together with it.
+func matchesFrom(prompt, typed string, position int) bool {
+	want := quotes.Graphemes(prompt)
+	got := quotes.Graphemes(typed)
+	position = min(position, max(len(got)-1, 0))
+	for i := position; i < len(got); i++ {
+		if i >= len(want) || got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
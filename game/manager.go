@@ -13,11 +13,28 @@ import (
 
 // Manager handles game sessions and player matchmaking
 type Manager struct {
-	sessions     map[string]*Session
-	players      map[string]*Player
-	lobbies      map[string]*Lobby
-	mu           sync.RWMutex
-	quoteFetcher *quotes.Fetcher
+	sessions    map[string]*Session
+	players     map[string]*Player
+	lobbies     map[string]*Lobby
+	mu          sync.RWMutex
+	quoteSource quotes.Source
+	quoteFilter quotes.Filter
+	normalizer  *quotes.Normalizer
+	pools       map[poolKey]*quotePool
+	poolSize    int
+	history     *quotes.History
+	leaderboard *quotes.Leaderboard
+	submissions *quotes.SubmissionQueue
+	ratings     *quotes.Ratings
+}
+
+// DefaultPrefetchSize is how many quotes each pool keeps ready by default
+const DefaultPrefetchSize = 5
+
+// poolKey identifies the prefetch pool for a lobby's difficulty and language
+type poolKey struct {
+	difficulty quotes.Difficulty
+	language   quotes.Language
 }
 
 // Lobby represents a waiting area for players
@@ -25,22 +42,218 @@ type Lobby struct {
 	ID         string             `json:"id"`
 	Players    map[string]*Player `json:"players"`
 	MaxPlayers int                `json:"max_players"`
+	Difficulty quotes.Difficulty  `json:"difficulty,omitempty"`
+	Language   quotes.Language    `json:"language,omitempty"`
 	CreatedAt  time.Time          `json:"created_at"`
 	mu         sync.RWMutex
 }
 
-// NewManager creates a new game manager
+// NewManager creates a new game manager backed by the embedded quote library
 func NewManager() *Manager {
+	return NewManagerWithSource(quotes.DefaultSource())
+}
+
+// NewManagerWithSource creates a new game manager that draws quotes from source
+func NewManagerWithSource(source quotes.Source) *Manager {
 	return &Manager{
-		sessions:     make(map[string]*Session),
-		players:      make(map[string]*Player),
-		lobbies:      make(map[string]*Lobby),
-		quoteFetcher: quotes.NewFetcher(),
+		sessions:    make(map[string]*Session),
+		players:     make(map[string]*Player),
+		lobbies:     make(map[string]*Lobby),
+		quoteSource: source,
+		normalizer:  quotes.DefaultNormalizer(),
+		pools:       make(map[poolKey]*quotePool),
+		poolSize:    DefaultPrefetchSize,
 	}
 }
 
-// AddPlayer adds a player to the system
-func (m *Manager) AddPlayer(playerID, playerName string) (*Player, error) {
+// SetQuoteFilter restricts the quotes used for new sessions. The filter's
+// language is the default for players who do not pick one.
+func (m *Manager) SetQuoteFilter(filter quotes.Filter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.quoteFilter = filter
+	m.resetPools()
+}
+
+// SetNormalizer sets how quote text is cleaned up before it becomes a prompt
+func (m *Manager) SetNormalizer(normalizer *quotes.Normalizer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.normalizer = normalizer
+	m.resetPools()
+}
+
+// SetHistory sets where the quotes each player has typed are remembered so
+// races avoid repeating them; nil disables the check
+func (m *Manager) SetHistory(history *quotes.History) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history = history
+}
+
+// SetLeaderboard sets where finished races are recorded per quote; nil
+// disables recording
+func (m *Manager) SetLeaderboard(leaderboard *quotes.Leaderboard) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.leaderboard = leaderboard
+}
+
+// QuoteStats returns every result recorded for a quote
+func (m *Manager) QuoteStats(quoteID string) (quotes.QuoteStats, bool) {
+	m.mu.RLock()
+	leaderboard := m.leaderboard
+	m.mu.RUnlock()
+
+	if leaderboard == nil {
+		return quotes.QuoteStats{QuoteID: quoteID}, false
+	}
+	return leaderboard.Stats(quoteID)
+}
+
+// SetRatings sets where players' votes on quotes are kept; poorly rated
+// quotes come up less often and flagged ones stop coming up. Nil disables
+// voting.
+func (m *Manager) SetRatings(ratings *quotes.Ratings) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ratings = ratings
+	m.resetPools()
+}
+
+// RateQuote records a player's vote on the quote of their session under
+// their voter key, the fingerprint of the SSH key they connected with
+func (m *Manager) RateQuote(sessionID, playerID, voter string, vote quotes.Vote) error {
+	m.mu.RLock()
+	ratings := m.ratings
+	session, exists := m.sessions[sessionID]
+	m.mu.RUnlock()
+
+	if ratings == nil {
+		return fmt.Errorf("quote ratings are disabled")
+	}
+	if voter == "" {
+		return fmt.Errorf("connect with an SSH key to rate quotes")
+	}
+	if !exists || session.Quote() == nil {
+		return fmt.Errorf("session not found")
+	}
+	if _, exists := session.GetPlayer(playerID); !exists {
+		return fmt.Errorf("player not in session")
+	}
+	return ratings.Vote(session.Quote(), voter, vote)
+}
+
+// QuoteRating returns the votes cast on a quote and the vote of one voter
+func (m *Manager) QuoteRating(quoteID, voter string) (quotes.Rating, quotes.Vote, bool) {
+	m.mu.RLock()
+	ratings := m.ratings
+	m.mu.RUnlock()
+
+	if ratings == nil {
+		return quotes.Rating{}, "", false
+	}
+	vote, _ := ratings.VoteOf(quoteID, voter)
+	return ratings.Get(quoteID), vote, true
+}
+
+// SetSubmissions sets the queue players submit new quotes to; nil disables
+// submissions
+func (m *Manager) SetSubmissions(submissions *quotes.SubmissionQueue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.submissions = submissions
+}
+
+// Submissions returns the quote submission queue, or nil if disabled
+func (m *Manager) Submissions() *quotes.SubmissionQueue {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.submissions
+}
+
+// SetPrefetchSize sets how many quotes are kept ready for new sessions
+func (m *Manager) SetPrefetchSize(size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.poolSize = size
+	m.resetPools()
+}
+
+// PrefetchQuotes starts filling the quote pool for the default difficulty
+// and language so the first race does not have to wait
+func (m *Manager) PrefetchQuotes() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.quotePool(quotes.DifficultyAny, m.defaultLanguage())
+}
+
+// DefaultLanguage returns the language players race in unless they pick one
+func (m *Manager) DefaultLanguage() quotes.Language {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.defaultLanguage()
+}
+
+// defaultLanguage returns the language of the server's quote filter. The
+// caller must hold the lock.
+func (m *Manager) defaultLanguage() quotes.Language {
+	if m.quoteFilter.Language == quotes.LanguageAny {
+		return quotes.DefaultLanguage
+	}
+	return m.quoteFilter.Language
+}
+
+// Close stops the background quote prefetching
+func (m *Manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resetPools()
+}
+
+// quotePool returns the prefetch pool for a lobby difficulty and language,
+// creating it on first use. The caller must hold the write lock.
+func (m *Manager) quotePool(difficulty quotes.Difficulty, language quotes.Language) *quotePool {
+	key := poolKey{difficulty: difficulty, language: language}
+	if pool, exists := m.pools[key]; exists {
+		return pool
+	}
+
+	filter := m.quoteFilter
+	if difficulty != quotes.DifficultyAny {
+		filter.Difficulty = difficulty
+	}
+	if language != quotes.LanguageAny {
+		filter.Language = language
+	}
+	pool := newQuotePool(m.quoteSource, filter, m.normalizer, m.ratings, m.poolSize)
+	m.pools[key] = pool
+	return pool
+}
+
+// resetPools stops and discards every prefetch pool, e.g. after the quote
+// settings change. The caller must hold the write lock.
+func (m *Manager) resetPools() {
+	for key, pool := range m.pools {
+		pool.Stop()
+		delete(m.pools, key)
+	}
+}
+
+// AddPlayer adds a player to the system, racing in language or, for
+// LanguageAny, the server's default language
+func (m *Manager) AddPlayer(playerID, playerName string, language quotes.Language) (*Player, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -50,6 +263,10 @@ func (m *Manager) AddPlayer(playerID, playerName string) (*Player, error) {
 	}
 
 	player := NewPlayer(playerID, playerName, "")
+	player.Language = language
+	if language == quotes.LanguageAny {
+		player.Language = m.defaultLanguage()
+	}
 	m.players[playerID] = player
 
 	log.Printf("Player %s (%s) added to system", playerName, playerID)
@@ -90,22 +307,28 @@ func (m *Manager) GetPlayer(playerID string) (*Player, bool) {
 	return player, exists
 }
 
-// CreateLobby creates a new lobby for players to wait
-func (m *Manager) CreateLobby(maxPlayers int) (*Lobby, error) {
+// CreateLobby creates a new lobby for players racing in a language to wait
+func (m *Manager) CreateLobby(maxPlayers int, language quotes.Language) (*Lobby, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	return m.createLobby(maxPlayers, language), nil
+}
+
+// createLobby creates a lobby. The caller must hold the write lock.
+func (m *Manager) createLobby(maxPlayers int, language quotes.Language) *Lobby {
 	lobbyID := uuid.New().String()
 	lobby := &Lobby{
 		ID:         lobbyID,
 		Players:    make(map[string]*Player),
 		MaxPlayers: maxPlayers,
+		Language:   language,
 		CreatedAt:  time.Now(),
 	}
 
 	m.lobbies[lobbyID] = lobby
-	log.Printf("Created lobby %s with max %d players", lobbyID, maxPlayers)
-	return lobby, nil
+	log.Printf("Created %s lobby %s with max %d players", language.Label(), lobbyID, maxPlayers)
+	return lobby
 }
 
 // JoinLobby adds a player to a lobby
@@ -147,42 +370,178 @@ func (m *Manager) LeaveLobby(playerID, lobbyID string) {
 	}
 }
 
-// StartSessionFromLobby starts a session from a lobby
-func (m *Manager) StartSessionFromLobby(lobbyID string) (*Session, error) {
+// SetPlayerLanguage sets the language a player races in and moves them from
+// their lobby to one of players racing in that language, creating one if
+// none has room. It returns the player's new lobby.
+func (m *Manager) SetPlayerLanguage(playerID string, language quotes.Language) (*Lobby, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	player, exists := m.players[playerID]
+	if !exists {
+		return nil, fmt.Errorf("player not found")
+	}
+	current, exists := m.lobbies[player.SessionID]
+	if !exists {
+		return nil, fmt.Errorf("player is not in a lobby")
+	}
+
+	player.Language = language
+	if current.Language == language {
+		return current, nil
+	}
+
+	var next *Lobby
+	for _, lobby := range m.lobbies {
+		if lobby.Language == language && len(lobby.GetPlayers()) < lobby.MaxPlayers {
+			next = lobby
+			break
+		}
+	}
+	if next == nil {
+		next = m.createLobby(current.MaxPlayers, language)
+	}
+	if err := next.AddPlayer(player); err != nil {
+		return nil, err
+	}
+
+	current.RemovePlayer(playerID)
+	if len(current.GetPlayers()) == 0 {
+		delete(m.lobbies, current.ID)
+	}
+	player.SessionID = next.ID
+
+	// Start prefetching in the new language before the race begins
+	m.quotePool(next.GetDifficulty(), language)
+
+	log.Printf("Player %s moved to %s lobby %s", playerID, language.Label(), next.ID)
+	return next, nil
+}
+
+// SetLobbyDifficulty sets the quote difficulty a lobby will race on;
+// DifficultyAny falls back to the server's quote filter
+func (m *Manager) SetLobbyDifficulty(lobbyID string, difficulty quotes.Difficulty) error {
+	m.mu.Lock()
 	lobby, exists := m.lobbies[lobbyID]
+	if exists {
+		// Start prefetching for the new difficulty before the race begins
+		m.quotePool(difficulty, lobby.Language)
+	}
+	m.mu.Unlock()
+
 	if !exists {
-		return nil, fmt.Errorf("lobby not found")
+		return fmt.Errorf("lobby not found")
 	}
 
+	lobby.mu.Lock()
+	lobby.Difficulty = difficulty
+	lobby.mu.Unlock()
+
+	log.Printf("Lobby %s set difficulty to %s", lobbyID, difficulty.Label())
+	return nil
+}
+
+// StartSessionFromLobby starts a session from a lobby
+func (m *Manager) StartSessionFromLobby(lobbyID string) (*Session, error) {
+	// Claim the lobby so nobody else starts it, then pick the quote without
+	// holding the lock: an empty pool searches its library directly
+	m.mu.Lock()
+	lobby, exists := m.lobbies[lobbyID]
+	if !exists {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("lobby not found")
+	}
 	if len(lobby.Players) < 2 {
+		m.mu.Unlock()
 		return nil, fmt.Errorf("not enough players to start session")
 	}
+	delete(m.lobbies, lobbyID)
+	pool := m.quotePool(lobby.GetDifficulty(), lobby.Language)
+	history := m.history
+	m.mu.Unlock()
+
+	players := lobby.GetPlayers()
+	quote := pickQuote(pool, history, players)
 
-	// Fetch a random quote
-	quote := m.quoteFetcher.FetchRandomQuoteWithFallback()
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
 	// Create session
 	sessionID := uuid.New().String()
-	session := NewSession(sessionID, quote.Content, quote.Author, lobby.MaxPlayers)
+	session := NewSessionForQuote(sessionID, quote, lobby.MaxPlayers)
 
-	// Add all players from lobby to session
-	for _, player := range lobby.Players {
-		player.SessionID = sessionID
+	// Add the players from the lobby who are still connected
+	for _, player := range players {
+		if _, connected := m.players[player.ID]; !connected {
+			lobby.RemovePlayer(player.ID)
+			continue
+		}
 		session.AddPlayer(player)
 	}
+	if err := session.Start(); err != nil {
+		// Everyone else left while the quote was picked; keep waiting
+		if len(lobby.GetPlayers()) > 0 {
+			m.lobbies[lobbyID] = lobby
+		}
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
 
+	for _, player := range players {
+		if _, connected := m.players[player.ID]; connected {
+			player.SessionID = sessionID
+		}
+	}
 	m.sessions[sessionID] = session
 
-	// Remove lobby
-	delete(m.lobbies, lobbyID)
-
 	log.Printf("Started session %s with %d players", sessionID, len(session.Players))
 	return session, nil
 }
 
+// pickQuote takes a quote from pool that none of the players has typed and
+// records it in their history
+func pickQuote(pool *quotePool, history *quotes.History, players []*Player) *quotes.Quote {
+	playerIDs := make([]string, 0, len(players))
+	for _, player := range players {
+		playerIDs = append(playerIDs, player.ID)
+	}
+
+	var seen func(*quotes.Quote) bool
+	if history != nil {
+		seen = func(q *quotes.Quote) bool {
+			return history.SeenByAny(playerIDs, q)
+		}
+	}
+
+	quote, exhausted := pool.Take(seen)
+	if history == nil {
+		return quote
+	}
+	if exhausted {
+		// Everyone has typed every quote for this lobby; start that cycle
+		// over without forgetting quotes typed in other languages or levels
+		if err := history.Forget(playerIDs, pool.Matches()); err != nil {
+			log.Printf("Failed to reset quote history: %v", err)
+		}
+	}
+	if err := history.RecordAll(playerIDs, quote); err != nil {
+		log.Printf("Failed to record quote history: %v", err)
+	}
+	return quote
+}
+
+// ResetHistory forgets every quote a player has typed, so any of them can
+// come up again
+func (m *Manager) ResetHistory(playerID string) error {
+	m.mu.RLock()
+	history := m.history
+	m.mu.RUnlock()
+
+	if history == nil {
+		return fmt.Errorf("quote history is not kept")
+	}
+	return history.Reset(playerID)
+}
+
 // GetSession returns a session by ID
 func (m *Manager) GetSession(sessionID string) (*Session, bool) {
 	m.mu.RLock()
@@ -201,53 +560,113 @@ func (m *Manager) GetLobby(lobbyID string) (*Lobby, bool) {
 	return lobby, exists
 }
 
-// GetAvailableLobbies returns all lobbies that can accept more players
-func (m *Manager) GetAvailableLobbies() []*Lobby {
+// GetAvailableLobbies returns all lobbies racing in a language that can
+// accept more players
+func (m *Manager) GetAvailableLobbies(language quotes.Language) []*Lobby {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	var available []*Lobby
 	for _, lobby := range m.lobbies {
-		if len(lobby.Players) < lobby.MaxPlayers {
+		if lobby.Language == language && len(lobby.Players) < lobby.MaxPlayers {
 			available = append(available, lobby)
 		}
 	}
 	return available
 }
 
-// UpdatePlayerProgress updates a player's progress in their session
-func (m *Manager) UpdatePlayerProgress(playerID, typedInput string) error {
-	m.mu.RLock()
-	session, exists := m.sessions[playerID]
-	m.mu.RUnlock()
+// TypeKey applies text a player typed to their input in their session
+func (m *Manager) TypeKey(playerID, text string) error {
+	session := m.sessionOf(playerID)
+	if session == nil {
+		return fmt.Errorf("player not in any session")
+	}
 
-	if !exists {
-		// Try to find session by player
-		for _, s := range m.sessions {
-			if _, playerExists := s.GetPlayer(playerID); playerExists {
-				session = s
-				break
-			}
-		}
+	finished, err := session.TypeKey(playerID, text)
+	if err != nil {
+		return err
+	}
+	if finished {
+		m.recordResult(session, playerID)
 	}
+	return nil
+}
 
+// Backspace applies a backspace a player pressed in their session
+func (m *Manager) Backspace(playerID string) error {
+	session := m.sessionOf(playerID)
 	if session == nil {
 		return fmt.Errorf("player not in any session")
 	}
+	return session.Backspace(playerID)
+}
+
+// PlayerSessionID returns the ID of the lobby or session a player is in
+func (m *Manager) PlayerSessionID(playerID string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	player, exists := m.players[playerID]
+	if !exists {
+		return "", false
+	}
+	return player.SessionID, true
+}
 
-	session.UpdatePlayerProgress(playerID, typedInput)
+// sessionOf returns the session a player is racing in, or nil
+func (m *Manager) sessionOf(playerID string) *Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, session := range m.sessions {
+		if _, exists := session.GetPlayer(playerID); exists {
+			return session
+		}
+	}
 	return nil
 }
 
+// recordResult adds a player's finished race to the quote leaderboard
+func (m *Manager) recordResult(session *Session, playerID string) {
+	m.mu.RLock()
+	leaderboard := m.leaderboard
+	m.mu.RUnlock()
+
+	quote := session.Quote()
+	player, exists := session.Snapshot(playerID)
+	if leaderboard == nil || quote == nil || !exists {
+		return
+	}
+
+	err := leaderboard.Record(quote, quotes.Result{
+		Player:   player.Name,
+		WPM:      player.Score.NetWPM,
+		Accuracy: player.Score.Accuracy,
+		Seconds:  player.Elapsed().Seconds(),
+		Mode:     quotes.ModeRace,
+		At:       player.EndTime,
+	})
+	if err != nil {
+		log.Printf("Failed to record result for %s: %v", playerID, err)
+	}
+}
+
 // GetSystemStatus returns the current system status
 func (m *Manager) GetSystemStatus() SystemStatus {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	ready := 0
+	for _, pool := range m.pools {
+		ready += pool.Ready()
+	}
+
 	return SystemStatus{
 		TotalPlayers:   len(m.players),
 		ActiveSessions: len(m.sessions),
 		ActiveLobbies:  len(m.lobbies),
+		QuotesReady:    ready,
+		QuoteSources:   quotes.HealthOf(m.quoteSource),
 	}
 }
 
@@ -256,6 +675,9 @@ type SystemStatus struct {
 	TotalPlayers   int `json:"total_players"`
 	ActiveSessions int `json:"active_sessions"`
 	ActiveLobbies  int `json:"active_lobbies"`
+	QuotesReady    int `json:"quotes_ready"`
+	// QuoteSources reports the health of remote quote sources
+	QuoteSources []quotes.SourceHealth `json:"quote_sources,omitempty"`
 }
 
 // Lobby methods
@@ -289,6 +711,13 @@ func (l *Lobby) GetPlayers() []*Player {
 	return players
 }
 
+func (l *Lobby) GetDifficulty() quotes.Difficulty {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.Difficulty
+}
+
 func (l *Lobby) IsReady() bool {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
@@ -2,23 +2,28 @@ package game
 
 import (
 	"time"
+
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/quotes"
 )
 
 // Player represents a player in the game
 type Player struct {
-	ID           string    `json:"id"`
-	Name         string    `json:"name"`
-	SessionID    string    `json:"session_id"`
-	CurrentPos   int       `json:"current_pos"`
-	TypedInput   string    `json:"typed_input"`
-	StartTime    time.Time `json:"start_time"`
-	EndTime      time.Time `json:"end_time"`
-	IsFinished   bool      `json:"is_finished"`
-	WPM          float64   `json:"wpm"`
-	Accuracy     float64   `json:"accuracy"`
-	CorrectChars int       `json:"correct_chars"`
-	TotalChars   int       `json:"total_chars"`
-	LastUpdate   time.Time `json:"last_update"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	SessionID string `json:"session_id"`
+	// Language is the language the player races in
+	Language   quotes.Language `json:"language,omitempty"`
+	CurrentPos int             `json:"current_pos"`
+	TypedInput string          `json:"typed_input"`
+	StartTime  time.Time       `json:"start_time"`
+	EndTime    time.Time       `json:"end_time"`
+	IsFinished bool            `json:"is_finished"`
+	// Score is the player's speed, accuracy and errors in their race
+	Score      scoring.Score `json:"score"`
+	LastUpdate time.Time     `json:"last_update"`
+	// Keystrokes logs every key the player pressed in their current race
+	Keystrokes []scoring.Keystroke `json:"keystrokes,omitempty"`
 }
 
 // NewPlayer creates a new player
@@ -29,82 +34,70 @@ func NewPlayer(id, name, sessionID string) *Player {
 		SessionID:  sessionID,
 		CurrentPos: 0,
 		TypedInput: "",
-		StartTime:  time.Now(),
 		IsFinished: false,
-		WPM:        0.0,
-		Accuracy:   0.0,
 		LastUpdate: time.Now(),
 	}
 }
 
+// reset clears the player's progress for a new race on prompt
+func (p *Player) reset(prompt string) {
+	p.TypedInput = SkipIndentation(prompt, "")
+	p.CurrentPos = quotes.GraphemeCount(p.TypedInput)
+	p.Keystrokes = nil
+	p.StartTime = time.Time{}
+	p.EndTime = time.Time{}
+	p.IsFinished = false
+	p.Score = scoring.Score{}
+}
+
+// start starts the player's race clock at the GO moment
+func (p *Player) start(at time.Time) {
+	p.StartTime = at
+	p.LastUpdate = at
+}
+
 // UpdateProgress updates the player's typing progress
 func (p *Player) UpdateProgress(typedInput string, prompt string) {
 	p.TypedInput = typedInput
-	p.CurrentPos = len(typedInput)
+	p.CurrentPos = quotes.GraphemeCount(typedInput)
 	p.LastUpdate = time.Now()
-
-	// Calculate accuracy
-	p.calculateAccuracy(prompt)
-
-	// Calculate WPM
-	p.calculateWPM()
+	p.score(prompt)
 }
 
-// calculateAccuracy calculates the player's typing accuracy
-func (p *Player) calculateAccuracy(prompt string) {
-	if len(prompt) == 0 {
-		p.Accuracy = 0.0
-		return
-	}
-
-	correct := 0
-	total := len(p.TypedInput)
-	if total > len(prompt) {
-		total = len(prompt)
-	}
-
-	for i := 0; i < total; i++ {
-		if i < len(p.TypedInput) && i < len(prompt) && p.TypedInput[i] == prompt[i] {
-			correct++
-		}
-	}
-
-	p.CorrectChars = correct
-	p.TotalChars = total
-
-	if total > 0 {
-		p.Accuracy = float64(correct) / float64(total) * 100.0
-	} else {
-		p.Accuracy = 0.0
+// score scores the player's race up to now, or up to when they finished
+func (p *Player) score(prompt string) {
+	end := time.Now()
+	if p.IsFinished {
+		end = p.EndTime
 	}
+	p.Score = scoring.Compute(prompt, p.TypedInput, p.Keystrokes, p.StartTime, end)
 }
 
-// calculateWPM calculates words per minute
-func (p *Player) calculateWPM() {
+// Elapsed returns how long the player has been racing since GO, up to the
+// moment they finished
+func (p *Player) Elapsed() time.Duration {
+	if p.StartTime.IsZero() {
+		return 0
+	}
 	if p.IsFinished {
-		// Use end time for final WPM calculation
-		elapsed := p.EndTime.Sub(p.StartTime).Minutes()
-		if elapsed > 0 {
-			// WPM = (correct characters / 5) / minutes
-			p.WPM = float64(p.CorrectChars) / 5.0 / elapsed
-		}
-	} else {
-		// Use current time for live WPM calculation
-		elapsed := time.Since(p.StartTime).Minutes()
-		if elapsed > 0 {
-			p.WPM = float64(p.CorrectChars) / 5.0 / elapsed
-		}
+		return p.EndTime.Sub(p.StartTime)
 	}
+	return time.Since(p.StartTime)
 }
 
-// Finish marks the player as finished and calculates final stats
-func (p *Player) Finish() {
+// Finish marks the player as finished at their last keystroke and scores
+// their race on prompt
+func (p *Player) Finish(prompt string) {
 	p.IsFinished = true
 	p.EndTime = time.Now()
-	p.calculateWPM()
+	if n := len(p.Keystrokes); n > 0 {
+		p.EndTime = p.Keystrokes[n-1].At
+	}
+	p.score(prompt)
 }
 
-// GetProgress returns the progress percentage (0-100)
+// GetProgress returns the progress percentage (0-100) through a prompt of
+// promptLength grapheme clusters
 func (p *Player) GetProgress(promptLength int) float64 {
 	if promptLength == 0 {
 		return 0.0
@@ -116,4 +109,3 @@ func (p *Player) GetProgress(promptLength int) float64 {
 func (p *Player) IsComplete(promptLength int) bool {
 	return p.CurrentPos >= promptLength
 }
-
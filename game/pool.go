@@ -0,0 +1,225 @@
+package game
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"typeracer-tui/quotes"
+)
+
+// Limits on prompts accepted into the prefetch pool
+const (
+	minPromptLength = 10
+	maxPromptLength = 1000
+)
+
+// maxPrefetchFailures is how many bad quotes in a row stop a refill until
+// the next quote is taken
+const maxPrefetchFailures = 5
+
+// quotePool keeps a buffer of validated, normalized quotes for one filter
+// and refills it in the background so taking a quote never waits on the
+// network
+type quotePool struct {
+	source     quotes.Source
+	filter     quotes.Filter
+	normalizer *quotes.Normalizer
+	ratings    *quotes.Ratings
+	ready      chan *quotes.Quote
+	refill     chan struct{}
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+// newQuotePool creates a pool holding up to size quotes and starts filling
+// it, passing over quotes in proportion to how badly players rated them
+func newQuotePool(source quotes.Source, filter quotes.Filter, normalizer *quotes.Normalizer, ratings *quotes.Ratings, size int) *quotePool {
+	if size < 1 {
+		size = 1
+	}
+
+	pool := &quotePool{
+		source:     source,
+		filter:     filter,
+		normalizer: normalizer,
+		ratings:    ratings,
+		ready:      make(chan *quotes.Quote, size),
+		refill:     make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	go pool.run()
+	pool.requestRefill()
+	return pool
+}
+
+// Take returns a prefetched quote that seen does not reject, without
+// blocking on the quote source. When nothing suitable is prefetched,
+// in-memory libraries are searched directly; other sources fall back to a
+// prefetched repeat or to the embedded library while the pool refills in
+// the background. The second result reports that every quote matching the
+// filter has been seen.
+func (p *quotePool) Take(seen func(*quotes.Quote) bool) (*quotes.Quote, bool) {
+	defer p.requestRefill()
+	if seen == nil {
+		seen = func(*quotes.Quote) bool { return false }
+	}
+
+	// Set aside prefetched quotes that were seen and put them back after
+	var skipped []*quotes.Quote
+	defer func() {
+		for _, quote := range skipped {
+			select {
+			case p.ready <- quote:
+			default:
+			}
+		}
+	}()
+
+collect:
+	for len(skipped) < cap(p.ready) {
+		select {
+		case quote := <-p.ready:
+			if !seen(quote) {
+				return quote, false
+			}
+			skipped = append(skipped, quote)
+		default:
+			break collect
+		}
+	}
+
+	if quotes.Listable(p.source) {
+		return p.search(p.source, seen)
+	}
+
+	if len(skipped) > 0 {
+		quote := skipped[0]
+		skipped = skipped[1:]
+		return quote, false
+	}
+	// Asking the source could wait on the network, so race on an embedded
+	// quote this time
+	quote, _ := p.search(quotes.DefaultLibrary(), seen)
+	return quote, false
+}
+
+// search picks a quote from a listable source that seen does not reject and
+// that fits the pool, skipping poorly rated quotes unless they are all that
+// is left. The second result reports that every fitting match was seen.
+func (p *quotePool) search(source quotes.Source, seen func(*quotes.Quote) bool) (*quotes.Quote, bool) {
+	fits := func(quote *quotes.Quote) bool {
+		return p.fits(p.normalizer.Quote(quote))
+	}
+
+	quote, err := quotes.PickUnseen(source, p.filter, func(quote *quotes.Quote) bool {
+		return seen(quote) || !fits(quote) || !p.ratings.Keep(quote)
+	})
+	if errors.Is(err, quotes.ErrPoolExhausted) {
+		quote, err = quotes.PickUnseen(source, p.filter, func(quote *quotes.Quote) bool {
+			return seen(quote) || !fits(quote)
+		})
+	}
+	if err == nil {
+		return p.normalizer.Quote(quote), false
+	}
+
+	// Every fitting quote was seen; repeat one of them
+	quote, err = quotes.PickUnseen(source, p.filter, func(quote *quotes.Quote) bool {
+		return !fits(quote)
+	})
+	if err != nil {
+		quote = quotes.Pick(source, p.filter)
+	}
+	return p.normalizer.Quote(quote), true
+}
+
+// Matches returns every quote the pool's source holds for its filter, or
+// nil if the source cannot list them
+func (p *quotePool) Matches() []quotes.Quote {
+	return quotes.Matches(p.source, p.filter)
+}
+
+// Ready returns how many quotes are waiting in the pool
+func (p *quotePool) Ready() int {
+	return len(p.ready)
+}
+
+// Stop ends the refill goroutine
+func (p *quotePool) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+// requestRefill wakes the refill goroutine if it is not already pending
+func (p *quotePool) requestRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+// run refills the pool whenever asked until stopped
+func (p *quotePool) run() {
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-p.refill:
+			p.fill()
+		}
+	}
+}
+
+// fill fetches quotes until the pool is full, giving up for now after
+// repeated failures so a dead source is not hammered
+func (p *quotePool) fill() {
+	failures := 0
+	for len(p.ready) < cap(p.ready) && failures < maxPrefetchFailures {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		quote, err := p.source.Random(p.filter)
+		if err != nil {
+			failures++
+			log.Printf("Quote prefetch failed: %v", err)
+			continue
+		}
+
+		quote = p.normalizer.Quote(quote)
+		if !p.valid(quote) {
+			failures++
+			continue
+		}
+
+		select {
+		case p.ready <- quote:
+			failures = 0
+		default:
+			return
+		}
+	}
+}
+
+// valid reports whether a normalized quote fits the pool and survives its
+// rating
+func (p *quotePool) valid(quote *quotes.Quote) bool {
+	return p.fits(quote) && p.ratings.Keep(quote)
+}
+
+// fits reports whether a normalized quote is fit to race on
+func (p *quotePool) fits(quote *quotes.Quote) bool {
+	filter := p.filter
+	if quote.Source == quotes.GeneratedSource {
+		// Word and symbol drills are as hard as their settings make them, not
+		// as their scattered words score, so any level races on them
+		filter.Difficulty = quotes.DifficultyAny
+	}
+
+	length := quotes.GraphemeCount(quote.Content)
+	return length >= minPromptLength && length <= maxPromptLength && filter.Matches(quote)
+}
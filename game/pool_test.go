@@ -0,0 +1,223 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"typeracer-tui/quotes"
+)
+
+// poolQuotes are long enough to race on
+var poolQuotes = []quotes.Quote{
+	{Content: "The first quote in the pool."},
+	{Content: "The second quote in the pool."},
+	{Content: "The third quote in the pool."},
+}
+
+// seenContent returns a seen func rejecting quotes with the given contents
+func seenContent(contents ...string) func(*quotes.Quote) bool {
+	return func(q *quotes.Quote) bool {
+		for _, content := range contents {
+			if q.Content == content {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// hide flags a quote until its rating hides it
+func hide(t *testing.T, ratings *quotes.Ratings, q *quotes.Quote) {
+	t.Helper()
+	for _, voter := range []string{"SHA256:a", "SHA256:b"} {
+		if err := ratings.Vote(q, voter, quotes.VoteFlag); err != nil {
+			t.Fatalf("Vote() error: %v", err)
+		}
+	}
+}
+
+func TestQuotePoolTake(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        func() quotes.Source
+		hidden        []int
+		seen          []string
+		want          []string
+		wantExhausted bool
+	}{
+		{
+			name:   "takes a quote not yet seen",
+			source: func() quotes.Source { return quotes.NewLibrary(poolQuotes) },
+			seen:   []string{poolQuotes[0].Content, poolQuotes[1].Content},
+			want:   []string{poolQuotes[2].Content},
+		},
+		{
+			name:          "reports an exhausted library",
+			source:        func() quotes.Source { return quotes.NewLibrary(poolQuotes) },
+			seen:          []string{poolQuotes[0].Content, poolQuotes[1].Content, poolQuotes[2].Content},
+			want:          []string{poolQuotes[0].Content, poolQuotes[1].Content, poolQuotes[2].Content},
+			wantExhausted: true,
+		},
+		{
+			name:   "passes over hidden quotes",
+			source: func() quotes.Source { return quotes.NewLibrary(poolQuotes) },
+			hidden: []int{0, 1},
+			want:   []string{poolQuotes[2].Content},
+		},
+		{
+			name:   "serves a hidden quote when nothing else is left",
+			source: func() quotes.Source { return quotes.NewLibrary(poolQuotes) },
+			hidden: []int{0},
+			seen:   []string{poolQuotes[1].Content, poolQuotes[2].Content},
+			want:   []string{poolQuotes[0].Content},
+		},
+		{
+			name: "skips quotes too short to race on",
+			source: func() quotes.Source {
+				return quotes.NewLibrary([]quotes.Quote{{Content: "Short."}, poolQuotes[0]})
+			},
+			want: []string{poolQuotes[0].Content},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source()
+			ratings := quotes.NewRatings()
+			all := quotes.NewLibrary(poolQuotes).All()
+			for _, i := range tt.hidden {
+				hide(t, ratings, &all[i])
+			}
+
+			pool := newQuotePool(source, quotes.Filter{}, quotes.DefaultNormalizer(), ratings, 2)
+			defer pool.Stop()
+
+			// Draws are random, so take a few times
+			for i := 0; i < 10; i++ {
+				quote, exhausted := pool.Take(seenContent(tt.seen...))
+				if quote == nil {
+					t.Fatal("Take() = nil")
+				}
+				if exhausted != tt.wantExhausted {
+					t.Errorf("Take() exhausted = %v, want %v", exhausted, tt.wantExhausted)
+				}
+				if !slices.Contains(tt.want, quote.Content) {
+					t.Errorf("Take() = %q, want one of %q", quote.Content, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestQuotePoolTakeRepeatsPrefetched(t *testing.T) {
+	pool := newQuotePool(quotes.NewSequenceSource(poolQuotes[0]), quotes.Filter{}, quotes.DefaultNormalizer(), nil, 2)
+	defer pool.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for pool.Ready() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("pool holds %d quotes, want 2", pool.Ready())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	quote, exhausted := pool.Take(seenContent(poolQuotes[0].Content))
+	if exhausted {
+		t.Error("Take() reported a source it cannot list as exhausted")
+	}
+	if quote.Content != poolQuotes[0].Content {
+		t.Errorf("Take() = %q, want the prefetched %q", quote.Content, poolQuotes[0].Content)
+	}
+}
+
+// blockingSource is a source whose draws wait until released
+type blockingSource struct {
+	release chan struct{}
+}
+
+func (s blockingSource) Random(quotes.Filter) (*quotes.Quote, error) {
+	<-s.release
+	return nil, errors.New("source released")
+}
+
+func TestQuotePoolTakeDoesNotWaitForSource(t *testing.T) {
+	source := blockingSource{release: make(chan struct{})}
+	pool := newQuotePool(source, quotes.Filter{}, quotes.DefaultNormalizer(), nil, 2)
+	defer pool.Stop()
+	defer close(source.release)
+
+	taken := make(chan *quotes.Quote, 1)
+	go func() {
+		quote, _ := pool.Take(nil)
+		taken <- quote
+	}()
+
+	select {
+	case quote := <-taken:
+		if quote.Source != "embedded" {
+			t.Errorf("Take() source = %q, want an embedded quote", quote.Source)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Take() waited on the quote source")
+	}
+}
+
+func TestQuotePoolValid(t *testing.T) {
+	generated := &quotes.Quote{Content: "apple river stone cloud", Source: quotes.GeneratedSource}
+	otherDifficulty := quotes.DifficultyHard
+	if quotes.DifficultyOf(generated) == otherDifficulty {
+		otherDifficulty = quotes.DifficultyEasy
+	}
+
+	hidden := &quotes.Quote{ID: "hidden", Content: "A quote players flagged."}
+	ratings := quotes.NewRatings()
+	hide(t, ratings, hidden)
+
+	tests := []struct {
+		name   string
+		quote  *quotes.Quote
+		filter quotes.Filter
+		want   bool
+	}{
+		{
+			name:  "a quote of fair length",
+			quote: &quotes.Quote{Content: "Long enough to race on."},
+			want:  true,
+		},
+		{
+			name:  "too short",
+			quote: &quotes.Quote{Content: "Short."},
+		},
+		{
+			name:  "too long",
+			quote: &quotes.Quote{Content: strings.Repeat("a", maxPromptLength+1)},
+		},
+		{
+			name:   "outside the filter",
+			quote:  &quotes.Quote{Content: "Long enough to race on."},
+			filter: quotes.Filter{Tags: []string{"science"}},
+		},
+		{
+			name:  "hidden by its rating",
+			quote: hidden,
+		},
+		{
+			name:   "a drill at any difficulty",
+			quote:  generated,
+			filter: quotes.Filter{Difficulty: otherDifficulty},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &quotePool{filter: tt.filter, ratings: ratings}
+			if got := pool.valid(tt.quote); got != tt.want {
+				t.Errorf("valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Metric is the speed figure shown as the headline of a run
+type Metric string
+
+const (
+	MetricNetWPM   Metric = "net"
+	MetricGrossWPM Metric = "gross"
+	MetricRawCPM   Metric = "cpm"
+)
+
+// DefaultMetric is the headline shown unless players pick another
+const DefaultMetric = MetricNetWPM
+
+// metrics lists the metrics in the order players cycle through them
+var metrics = []Metric{MetricNetWPM, MetricGrossWPM, MetricRawCPM}
+
+// ParseMetric parses a metric name
+func ParseMetric(s string) (Metric, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "wpm":
+		return DefaultMetric, nil
+	case "raw":
+		return MetricRawCPM, nil
+	}
+	for _, metric := range metrics {
+		if s == string(metric) {
+			return metric, nil
+		}
+	}
+	return DefaultMetric, fmt.Errorf("unknown metric %q (want 'net', 'gross' or 'cpm')", s)
+}
+
+// Label returns the metric's display name
+func (m Metric) Label() string {
+	switch m {
+	case MetricGrossWPM:
+		return "Gross WPM"
+	case MetricRawCPM:
+		return "Raw CPM"
+	default:
+		return "Net WPM"
+	}
+}
+
+// Next cycles through the metrics
+func (m Metric) Next() Metric {
+	for i, metric := range metrics {
+		if metric == m {
+			return metrics[(i+1)%len(metrics)]
+		}
+	}
+	return metrics[0]
+}
+
+// Value returns the metric's figure from a score
+func (m Metric) Value(score Score) float64 {
+	switch m {
+	case MetricGrossWPM:
+		return score.GrossWPM
+	case MetricRawCPM:
+		return score.RawCPM
+	default:
+		return score.NetWPM
+	}
+}
+
+// Format renders the metric's figure from a score with its unit
+func (m Metric) Format(score Score) string {
+	if m == MetricRawCPM {
+		return fmt.Sprintf("%.0f CPM", score.RawCPM)
+	}
+	return fmt.Sprintf("%.1f WPM", m.Value(score))
+}
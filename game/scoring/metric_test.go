@@ -0,0 +1,61 @@
+package scoring
+
+import "testing"
+
+func TestParseMetric(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Metric
+		wantErr bool
+	}{
+		{input: "", want: MetricNetWPM},
+		{input: "wpm", want: MetricNetWPM},
+		{input: "net", want: MetricNetWPM},
+		{input: " Gross ", want: MetricGrossWPM},
+		{input: "cpm", want: MetricRawCPM},
+		{input: "raw", want: MetricRawCPM},
+		{input: "kph", want: DefaultMetric, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMetric(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMetric(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMetric(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetric(t *testing.T) {
+	score := Score{GrossWPM: 62.25, NetWPM: 58.04, RawCPM: 341.6}
+
+	tests := []struct {
+		metric     Metric
+		wantValue  float64
+		wantFormat string
+		wantNext   Metric
+	}{
+		{metric: MetricNetWPM, wantValue: 58.04, wantFormat: "58.0 WPM", wantNext: MetricGrossWPM},
+		{metric: MetricGrossWPM, wantValue: 62.25, wantFormat: "62.2 WPM", wantNext: MetricRawCPM},
+		{metric: MetricRawCPM, wantValue: 341.6, wantFormat: "342 CPM", wantNext: MetricNetWPM},
+		{metric: Metric("bogus"), wantValue: 58.04, wantFormat: "58.0 WPM", wantNext: MetricNetWPM},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.metric), func(t *testing.T) {
+			if got := tt.metric.Value(score); got != tt.wantValue {
+				t.Errorf("Value() = %v, want %v", got, tt.wantValue)
+			}
+			if got := tt.metric.Format(score); got != tt.wantFormat {
+				t.Errorf("Format() = %q, want %q", got, tt.wantFormat)
+			}
+			if got := tt.metric.Next(); got != tt.wantNext {
+				t.Errorf("Next() = %q, want %q", got, tt.wantNext)
+			}
+		})
+	}
+}
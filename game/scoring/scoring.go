@@ -0,0 +1,156 @@
+// Package scoring turns a typing run, the text typed and the keystrokes
+// that typed it, into speed, accuracy and error statistics. Practice,
+// lessons and multiplayer races are all scored here.
+package scoring
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"typeracer-tui/quotes"
+)
+
+// charsPerWord is the standard length of a word when measuring speed
+const charsPerWord = 5.0
+
+// Keystroke is one key pressed while typing. A log of keystrokes is the
+// source for statistics, replays and cheat detection, so it records what
+// was pressed rather than snapshots of the typed text.
+type Keystroke struct {
+	// Key is the text the key typed, empty for a backspace
+	Key       string `json:"key,omitempty"`
+	Backspace bool   `json:"backspace,omitempty"`
+	// At is when the key was pressed
+	At time.Time `json:"at"`
+	// Position is the grapheme cluster the cursor was on before the key
+	Position int `json:"position"`
+	// Correct reports whether the typed text matches the prompt at Position.
+	// For a backspace it reports whether the deleted text had been correct.
+	Correct bool `json:"correct"`
+}
+
+// Score is how well a prompt was typed. Characters are grapheme clusters.
+type Score struct {
+	Elapsed time.Duration `json:"elapsed"`
+	// GrossWPM is the typed characters, right or wrong, in words per minute
+	GrossWPM float64 `json:"gross_wpm"`
+	// NetWPM is GrossWPM less one word per minute for every uncorrected
+	// error
+	NetWPM float64 `json:"net_wpm"`
+	// RawCPM is every character keyed per minute, including those that
+	// were deleted again
+	RawCPM float64 `json:"raw_cpm"`
+	// Accuracy is the percentage of typed characters matching the prompt
+	Accuracy     float64 `json:"accuracy"`
+	CorrectChars int     `json:"correct_chars"`
+	TypedChars   int     `json:"typed_chars"`
+	// KeyedChars counts every character keyed, including deleted ones
+	KeyedChars int `json:"keyed_chars"`
+	// CorrectedErrors counts mistakes that were deleted again
+	CorrectedErrors int `json:"corrected_errors"`
+	// UncorrectedErrors counts typed characters still not matching the
+	// prompt
+	UncorrectedErrors int `json:"uncorrected_errors"`
+	// Consistency is 100 for an even pace and drops towards 0 the more the
+	// speed varies from second to second
+	Consistency float64 `json:"consistency"`
+}
+
+// Compute scores typed against prompt, given the keystrokes pressed between
+// start and end. A zero start means typing has not begun. Indentation the
+// game filled in is not counted as typed.
+func Compute(prompt, typed string, keystrokes []Keystroke, start, end time.Time) Score {
+	var score Score
+	score.CorrectChars, score.TypedChars = quotes.CompareGraphemes(prompt, typed)
+	skipped := skippedIndentation(prompt, typed)
+	score.CorrectChars -= skipped
+	score.TypedChars -= skipped
+	score.UncorrectedErrors = score.TypedChars - score.CorrectChars
+	if score.TypedChars > 0 {
+		score.Accuracy = float64(score.CorrectChars) / float64(score.TypedChars) * 100.0
+	}
+
+	for _, key := range keystrokes {
+		switch {
+		case key.Backspace && !key.Correct:
+			score.CorrectedErrors++
+		case !key.Backspace:
+			score.KeyedChars += quotes.GraphemeCount(key.Key)
+		}
+	}
+
+	if start.IsZero() || !end.After(start) {
+		return score
+	}
+	score.Elapsed = end.Sub(start)
+	minutes := score.Elapsed.Minutes()
+
+	score.GrossWPM = float64(score.TypedChars) / charsPerWord / minutes
+	score.NetWPM = max(score.GrossWPM-float64(score.UncorrectedErrors)/minutes, 0)
+	score.RawCPM = float64(score.KeyedChars) / minutes
+	score.Consistency = consistency(keystrokes, start, end)
+	return score
+}
+
+// skippedIndentation counts the indentation filled in for the player at the
+// start of lines, which was never typed and so is not scored. The game fills
+// in a line's indentation whenever everything before it was typed
+// correctly, so that is exactly the indentation following a correct prefix.
+func skippedIndentation(prompt, typed string) int {
+	skipped := 0
+	for start := 0; start <= len(typed) && typed[:start] == prompt[:min(start, len(prompt))]; {
+		end := start
+		for end < len(typed) && end < len(prompt) && (prompt[end] == ' ' || prompt[end] == '\t') && typed[end] == prompt[end] {
+			end++
+		}
+		skipped += end - start
+
+		next := strings.IndexByte(typed[start:], '\n')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return skipped
+}
+
+// consistency rates how steady the pace was from the variation of the
+// characters keyed in each whole second of the run. The last, partial
+// second is left out so it does not read as a slowdown.
+func consistency(keystrokes []Keystroke, start, end time.Time) float64 {
+	seconds := int(end.Sub(start).Seconds())
+	if seconds < 2 {
+		return 100.0
+	}
+
+	counts := make([]float64, seconds)
+	for _, key := range keystrokes {
+		if key.Backspace || key.At.Before(start) {
+			continue
+		}
+		second := int(key.At.Sub(start).Seconds())
+		if second >= seconds {
+			continue
+		}
+		counts[second] += float64(quotes.GraphemeCount(key.Key))
+	}
+
+	mean := 0.0
+	for _, count := range counts {
+		mean += count
+	}
+	mean /= float64(seconds)
+	if mean == 0 {
+		return 0.0
+	}
+
+	variance := 0.0
+	for _, count := range counts {
+		variance += (count - mean) * (count - mean)
+	}
+	deviation := math.Sqrt(variance / float64(seconds))
+
+	// One minus the coefficient of variation, as a percentage
+	return max(100.0*(1-deviation/mean), 0)
+}
@@ -0,0 +1,210 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// typeKeys returns correct keystrokes for each character of text, one
+// every interval from start
+func typeKeys(text string, interval time.Duration) []Keystroke {
+	var keys []Keystroke
+	for i, r := range []rune(text) {
+		keys = append(keys, Keystroke{
+			Key:      string(r),
+			At:       start.Add(time.Duration(i) * interval),
+			Position: i,
+			Correct:  true,
+		})
+	}
+	return keys
+}
+
+func TestCompute(t *testing.T) {
+	corrected := []Keystroke{
+		{Key: "a", At: start, Position: 0, Correct: true},
+		{Key: "x", At: start.Add(time.Second), Position: 1},
+		{Backspace: true, At: start.Add(2 * time.Second), Position: 2},
+		{Key: "b", At: start.Add(3 * time.Second), Position: 1, Correct: true},
+	}
+
+	tests := []struct {
+		name       string
+		prompt     string
+		typed      string
+		keystrokes []Keystroke
+		start, end time.Time
+		want       Score
+	}{
+		{
+			name:   "not started",
+			prompt: "abcde",
+			typed:  "ab",
+			want:   Score{Accuracy: 100, CorrectChars: 2, TypedChars: 2},
+		},
+		{
+			name:       "a clean minute",
+			prompt:     "abcdefghij",
+			typed:      "abcdefghij",
+			keystrokes: typeKeys("abcdefghij", 6*time.Second),
+			start:      start,
+			end:        start.Add(time.Minute),
+			want: Score{
+				Elapsed:      time.Minute,
+				GrossWPM:     2,
+				NetWPM:       2,
+				RawCPM:       10,
+				Accuracy:     100,
+				CorrectChars: 10,
+				TypedChars:   10,
+				KeyedChars:   10,
+			},
+		},
+		{
+			name:       "uncorrected errors lower net speed",
+			prompt:     "abcdefghij",
+			typed:      "abcdefghix",
+			keystrokes: typeKeys("abcdefghix", 6*time.Second),
+			start:      start,
+			end:        start.Add(time.Minute),
+			want: Score{
+				Elapsed:           time.Minute,
+				GrossWPM:          2,
+				NetWPM:            1,
+				RawCPM:            10,
+				Accuracy:          90,
+				CorrectChars:      9,
+				TypedChars:        10,
+				KeyedChars:        10,
+				UncorrectedErrors: 1,
+			},
+		},
+		{
+			name:       "corrected errors count every key",
+			prompt:     "ab",
+			typed:      "ab",
+			keystrokes: corrected,
+			start:      start,
+			end:        start.Add(30 * time.Second),
+			want: Score{
+				Elapsed:         30 * time.Second,
+				GrossWPM:        0.8,
+				NetWPM:          0.8,
+				RawCPM:          6,
+				Accuracy:        100,
+				CorrectChars:    2,
+				TypedChars:      2,
+				KeyedChars:      3,
+				CorrectedErrors: 1,
+			},
+		},
+		{
+			name:       "filled-in indentation is not typed",
+			prompt:     "if x {\n\t\treturn\n}",
+			typed:      "if x {\n\t\treturn\n}",
+			keystrokes: typeKeys("if x {\nreturn\n}", 3*time.Second),
+			start:      start,
+			end:        start.Add(time.Minute),
+			want: Score{
+				Elapsed:      time.Minute,
+				GrossWPM:     3,
+				NetWPM:       3,
+				RawCPM:       15,
+				Accuracy:     100,
+				CorrectChars: 15,
+				TypedChars:   15,
+				KeyedChars:   15,
+			},
+		},
+		{
+			name:       "indentation typed after a mistake counts",
+			prompt:     "if x {\n  y\n}",
+			typed:      "if z {\n  y",
+			keystrokes: typeKeys("if z {\n  y", 3*time.Second),
+			start:      start,
+			end:        start.Add(time.Minute),
+			want: Score{
+				Elapsed:           time.Minute,
+				GrossWPM:          2,
+				NetWPM:            1,
+				RawCPM:            10,
+				Accuracy:          90,
+				CorrectChars:      9,
+				TypedChars:        10,
+				KeyedChars:        10,
+				UncorrectedErrors: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(tt.prompt, tt.typed, tt.keystrokes, tt.start, tt.end)
+			// Consistency has its own test
+			got.Consistency = 0
+			if !scoresEqual(got, tt.want) {
+				t.Errorf("Compute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeConsistency(t *testing.T) {
+	tests := []struct {
+		name       string
+		keystrokes []Keystroke
+		end        time.Time
+		want       float64
+	}{
+		{
+			name:       "an even pace",
+			keystrokes: typeKeys("abcdefghijklmnopqrst", 200*time.Millisecond),
+			end:        start.Add(4 * time.Second),
+			want:       100,
+		},
+		{
+			name:       "a burst then nothing",
+			keystrokes: typeKeys("abcdefghijklmnopqrst", 10*time.Millisecond),
+			end:        start.Add(4 * time.Second),
+			want:       0,
+		},
+		{
+			name:       "too short to judge",
+			keystrokes: typeKeys("abc", 100*time.Millisecond),
+			end:        start.Add(1500 * time.Millisecond),
+			want:       100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var typed string
+			for _, key := range tt.keystrokes {
+				typed += key.Key
+			}
+			got := Compute(typed, typed, tt.keystrokes, start, tt.end).Consistency
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Consistency = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// scoresEqual compares scores, allowing for rounding in the rates
+func scoresEqual(a, b Score) bool {
+	close := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	return a.Elapsed == b.Elapsed &&
+		close(a.GrossWPM, b.GrossWPM) &&
+		close(a.NetWPM, b.NetWPM) &&
+		close(a.RawCPM, b.RawCPM) &&
+		close(a.Accuracy, b.Accuracy) &&
+		close(a.Consistency, b.Consistency) &&
+		a.CorrectChars == b.CorrectChars &&
+		a.TypedChars == b.TypedChars &&
+		a.KeyedChars == b.KeyedChars &&
+		a.CorrectedErrors == b.CorrectedErrors &&
+		a.UncorrectedErrors == b.UncorrectedErrors
+}
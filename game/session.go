@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/quotes"
 )
 
 // Session represents a game session
@@ -11,6 +14,7 @@ type Session struct {
 	ID         string             `json:"id"`
 	Prompt     string             `json:"prompt"`
 	Author     string             `json:"author"`
+	QuoteID    string             `json:"quote_id,omitempty"`
 	Players    map[string]*Player `json:"players"`
 	MaxPlayers int                `json:"max_players"`
 	StartTime  time.Time          `json:"start_time"`
@@ -18,6 +22,7 @@ type Session struct {
 	IsActive   bool               `json:"is_active"`
 	IsFinished bool               `json:"is_finished"`
 	Countdown  int                `json:"countdown"`
+	quote      *quotes.Quote
 	mu         sync.RWMutex
 }
 
@@ -35,6 +40,20 @@ func NewSession(id, prompt, author string, maxPlayers int) *Session {
 	}
 }
 
+// NewSessionForQuote creates a new game session racing on quote
+func NewSessionForQuote(id string, quote *quotes.Quote, maxPlayers int) *Session {
+	session := NewSession(id, quote.Content, quote.Author, maxPlayers)
+	session.QuoteID = quote.ID
+	session.quote = quote
+	return session
+}
+
+// Quote returns the quote being raced, or nil if the session was created
+// from a bare prompt
+func (s *Session) Quote() *quotes.Quote {
+	return s.quote
+}
+
 // AddPlayer adds a player to the session
 func (s *Session) AddPlayer(player *Player) error {
 	s.mu.Lock()
@@ -48,6 +67,7 @@ func (s *Session) AddPlayer(player *Player) error {
 		return fmt.Errorf("session has already started")
 	}
 
+	player.reset(s.Prompt)
 	s.Players[player.ID] = player
 	return nil
 }
@@ -103,7 +123,6 @@ func (s *Session) Start() error {
 	}
 
 	s.IsActive = true
-	s.StartTime = time.Now()
 	s.Countdown = 3
 
 	// Start countdown in a goroutine
@@ -123,26 +142,124 @@ func (s *Session) runCountdown() {
 	}
 
 	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Everyone's race clock starts at GO, not when they connected
 	s.Countdown = 0
-	s.mu.Unlock()
+	s.StartTime = time.Now()
+	for _, player := range s.Players {
+		player.start(s.StartTime)
+	}
+}
+
+// racing reports whether the countdown is over and the race still running
+func (s *Session) racing() bool {
+	return s.IsActive && s.Countdown == 0 && !s.IsFinished
 }
 
-// UpdatePlayerProgress updates a player's progress
-func (s *Session) UpdatePlayerProgress(playerID, typedInput string) {
+// TypeKey applies text a player typed and reports whether this key
+// finished the race for them. Keys are only accepted between GO and the
+// player finishing.
+func (s *Session) TypeKey(playerID, text string) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if player, exists := s.Players[playerID]; exists {
-		player.UpdateProgress(typedInput, s.Prompt)
+	player, err := s.racingPlayer(playerID)
+	if err != nil {
+		return false, err
+	}
+	player.Type(text, s.Prompt)
+	return s.finishPlayer(player), nil
+}
 
-		// Check if player finished
-		if player.IsComplete(len(s.Prompt)) && !player.IsFinished {
-			player.Finish()
-		}
+// Backspace applies a backspace a player pressed
+func (s *Session) Backspace(playerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		// Check if all players finished
-		s.checkCompletion()
+	player, err := s.racingPlayer(playerID)
+	if err != nil {
+		return err
 	}
+	player.Backspace(s.Prompt)
+	return nil
+}
+
+// racingPlayer returns a player who may type in the race right now
+func (s *Session) racingPlayer(playerID string) (*Player, error) {
+	player, exists := s.Players[playerID]
+	if !exists {
+		return nil, fmt.Errorf("player not in session")
+	}
+	if !s.racing() {
+		return nil, fmt.Errorf("race is not running")
+	}
+	if player.IsFinished {
+		return nil, fmt.Errorf("player has already finished")
+	}
+	return player, nil
+}
+
+// Keystrokes returns a copy of the keystrokes a player has pressed
+func (s *Session) Keystrokes(playerID string) []scoring.Keystroke {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	player, exists := s.Players[playerID]
+	if !exists {
+		return nil
+	}
+	return append([]scoring.Keystroke(nil), player.Keystrokes...)
+}
+
+// Snapshot returns a copy of a player's state as the session scores it,
+// with their live score, but without the keystroke log
+func (s *Session) Snapshot(playerID string) (Player, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	player, exists := s.Players[playerID]
+	if !exists {
+		return Player{}, false
+	}
+	return snapshot(player, s.Prompt), true
+}
+
+// Snapshots returns a copy of every player's state, as Snapshot does
+func (s *Session) Snapshots() []Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	players := make([]Player, 0, len(s.Players))
+	for _, player := range s.Players {
+		players = append(players, snapshot(player, s.Prompt))
+	}
+	return players
+}
+
+// snapshot copies a player, bringing the score of a player still racing
+// up to date
+func snapshot(player *Player, prompt string) Player {
+	snap := *player
+	if !snap.IsFinished {
+		snap.score(prompt)
+	}
+	snap.Keystrokes = nil
+	return snap
+}
+
+// finishPlayer finishes the race for a player who has typed the whole
+// prompt and reports whether they just finished
+func (s *Session) finishPlayer(player *Player) bool {
+	finished := false
+	if player.IsComplete(quotes.GraphemeCount(s.Prompt)) && !player.IsFinished {
+		player.Finish(s.Prompt)
+		finished = true
+	}
+
+	// Check if all players finished
+	s.checkCompletion()
+	return finished
 }
 
 // checkCompletion checks if all players have finished
@@ -165,15 +282,10 @@ func (s *Session) checkCompletion() {
 	}
 }
 
-// GetLeaderboard returns players sorted by completion time
-func (s *Session) GetLeaderboard() []*Player {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	players := make([]*Player, 0, len(s.Players))
-	for _, player := range s.Players {
-		players = append(players, player)
-	}
+// GetLeaderboard returns a snapshot of the players sorted by completion
+// time
+func (s *Session) GetLeaderboard() []Player {
+	players := s.Snapshots()
 
 	// Sort by finish time (earliest first)
 	for i := 0; i < len(players); i++ {
@@ -182,7 +294,7 @@ func (s *Session) GetLeaderboard() []*Player {
 				if players[i].EndTime.After(players[j].EndTime) {
 					players[i], players[j] = players[j], players[i]
 				}
-			} else if players[i].IsFinished && !players[j].IsFinished {
+			} else if !players[i].IsFinished && players[j].IsFinished {
 				// Finished players come first
 				players[i], players[j] = players[j], players[i]
 			}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"strings"
+
+	"typeracer-tui/quotes"
+)
+
+// TypeText appends text to the input and then skips any indentation at the
+// start of the next prompt line so only the code itself has to be typed. A
+// combining accent typed on its own joins the letter before it.
+func TypeText(prompt, typed, text string) string {
+	return SkipIndentation(prompt, quotes.ComposeText(typed+text))
+}
+
+// SkipIndentation fills in the prompt's leading whitespace when the cursor
+// sits at the start of a line and everything typed so far is correct. The
+// filled-in indentation is not scored.
+func SkipIndentation(prompt, typed string) string {
+	pos := len(typed)
+	if pos >= len(prompt) || !strings.HasPrefix(prompt, typed) {
+		return typed
+	}
+	if pos > 0 && prompt[pos-1] != '\n' {
+		return typed
+	}
+
+	end := pos
+	for end < len(prompt) && (prompt[end] == ' ' || prompt[end] == '\t') {
+		end++
+	}
+	return prompt[:end]
+}
+
+// DeleteText removes the last typed character, together with indentation
+// that was filled in automatically and the newline before it
+func DeleteText(prompt, typed string) string {
+	if len(typed) == 0 {
+		return typed
+	}
+
+	lineStart := strings.LastIndex(typed, "\n") + 1
+	indent := typed[lineStart:]
+	if indent != "" && strings.TrimLeft(indent, " \t") == "" && strings.HasPrefix(prompt, typed) {
+		if lineStart == 0 {
+			// Indentation before the first line is never typed by hand
+			return typed
+		}
+		return typed[:lineStart-1]
+	}
+	return quotes.TrimLastGrapheme(typed)
+}
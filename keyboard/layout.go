@@ -0,0 +1,224 @@
+// Package keyboard emulates logical keyboard layouts on a physical QWERTY
+// keyboard, so players can type Dvorak, Colemak or Workman without changing
+// their system keymap, and describes which key and finger types each
+// character.
+package keyboard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layout maps the keys of a US keyboard to the characters they type
+type Layout struct {
+	ID   string
+	Name string
+	// rows lists the unshifted and shifted characters of the number, top,
+	// home and bottom rows, left to right
+	rows [4][2]string
+	// keys maps each character to the key position that types it
+	keys map[rune]position
+}
+
+// position is a key on the physical keyboard
+type position struct {
+	row, column int
+	shifted     bool
+}
+
+// rowNames names the keyboard rows for hints
+var rowNames = [4]string{"number row", "top row", "home row", "bottom row"}
+
+var (
+	// QWERTY is the standard US layout, which every physical keyboard is
+	// assumed to send
+	QWERTY = newLayout("qwerty", "QWERTY", [4][2]string{
+		{"`1234567890-=", "~!@#$%^&*()_+"},
+		{"qwertyuiop[]\\", "QWERTYUIOP{}|"},
+		{"asdfghjkl;'", "ASDFGHJKL:\""},
+		{"zxcvbnm,./", "ZXCVBNM<>?"},
+	})
+
+	// Dvorak is the Dvorak Simplified Keyboard
+	Dvorak = newLayout("dvorak", "Dvorak", [4][2]string{
+		{"`1234567890[]", "~!@#$%^&*(){}"},
+		{"',.pyfgcrl/=\\", "\"<>PYFGCRL?+|"},
+		{"aoeuidhtns-", "AOEUIDHTNS_"},
+		{";qjkxbmwvz", ":QJKXBMWVZ"},
+	})
+
+	// Colemak keeps most punctuation and shortcuts where QWERTY has them
+	Colemak = newLayout("colemak", "Colemak", [4][2]string{
+		{"`1234567890-=", "~!@#$%^&*()_+"},
+		{"qwfpgjluy;[]\\", "QWFPGJLUY:{}|"},
+		{"arstdhneio'", "ARSTDHNEIO\""},
+		{"zxcvbkm,./", "ZXCVBKM<>?"},
+	})
+
+	// Workman favours the keys the fingers reach most easily
+	Workman = newLayout("workman", "Workman", [4][2]string{
+		{"`1234567890-=", "~!@#$%^&*()_+"},
+		{"qdrwbjfup;[]\\", "QDRWBJFUP:{}|"},
+		{"ashtgyneoi'", "ASHTGYNEOI\""},
+		{"zxmcvkl,./", "ZXMCVKL<>?"},
+	})
+)
+
+// layouts lists every supported layout in the order they are cycled
+var layouts = []*Layout{QWERTY, Dvorak, Colemak, Workman}
+
+// newLayout builds a layout's lookup tables from its rows
+func newLayout(id, name string, rows [4][2]string) *Layout {
+	layout := &Layout{
+		ID:   id,
+		Name: name,
+		rows: rows,
+		keys: make(map[rune]position),
+	}
+	for row := range rows {
+		for shift, characters := range rows[row] {
+			for column, character := range []rune(characters) {
+				layout.keys[character] = position{row: row, column: column, shifted: shift == 1}
+			}
+		}
+	}
+	return layout
+}
+
+// Layouts returns every supported layout
+func Layouts() []*Layout {
+	return append([]*Layout(nil), layouts...)
+}
+
+// ParseLayout finds a layout by name, ignoring case; an empty name is QWERTY
+func ParseLayout(name string) (*Layout, error) {
+	if name == "" {
+		return QWERTY, nil
+	}
+	for _, layout := range layouts {
+		if strings.EqualFold(layout.ID, name) {
+			return layout, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown keyboard layout %q (want qwerty, dvorak, colemak or workman)", name)
+}
+
+// IsQWERTY reports whether the layout types what the keyboard sends
+func (l *Layout) IsQWERTY() bool {
+	return l == nil || l == QWERTY
+}
+
+// Next returns the layout after this one, wrapping around
+func (l *Layout) Next() *Layout {
+	for i, layout := range layouts {
+		if layout == l {
+			return layouts[(i+1)%len(layouts)]
+		}
+	}
+	return QWERTY
+}
+
+// Remap translates text typed on a physical QWERTY keyboard into what the
+// same keys type on this layout. Characters QWERTY does not have are kept.
+func (l *Layout) Remap(text string) string {
+	if l.IsQWERTY() {
+		return text
+	}
+	return strings.Map(func(r rune) rune {
+		key, ok := QWERTY.keys[r]
+		if !ok {
+			return r
+		}
+		return l.at(key)
+	}, text)
+}
+
+// at returns the character a key position types on this layout
+func (l *Layout) at(key position) rune {
+	shift := 0
+	if key.shifted {
+		shift = 1
+	}
+	characters := []rune(l.rows[key.row][shift])
+	if key.column >= len(characters) {
+		return QWERTY.at(key)
+	}
+	return characters[key.column]
+}
+
+// Hint describes how to type a character on a layout
+type Hint struct {
+	// Key is the label of the physical QWERTY key to press
+	Key string
+	// Finger is the finger that presses it, e.g. "left index"
+	Finger string
+	// Row is the keyboard row the key is on
+	Row string
+	// Shift reports whether Shift must be held
+	Shift bool
+}
+
+// String renders the hint, e.g. "Shift + S key, left ring finger"
+func (h Hint) String() string {
+	hint := h.Key
+	if h.Shift {
+		hint = "Shift + " + hint
+	}
+	return fmt.Sprintf("%s, %s finger", hint, h.Finger)
+}
+
+// HintFor returns which physical key and finger type a character on this
+// layout
+func (l *Layout) HintFor(r rune) (Hint, bool) {
+	switch r {
+	case ' ':
+		return Hint{Key: "Space", Finger: "thumb"}, true
+	case '\n':
+		return Hint{Key: "Enter", Finger: "right little"}, true
+	case '\t':
+		return Hint{Key: "Tab", Finger: "left little"}, true
+	}
+
+	layout := l
+	if layout == nil {
+		layout = QWERTY
+	}
+	key, ok := layout.keys[r]
+	if !ok {
+		return Hint{}, false
+	}
+
+	label := string(QWERTY.at(position{row: key.row, column: key.column}))
+	return Hint{
+		Key:    strings.ToUpper(label) + " key",
+		Finger: finger(key),
+		Row:    rowNames[key.row],
+		Shift:  key.shifted,
+	}, true
+}
+
+// finger returns which finger presses a key in standard touch typing
+func finger(key position) string {
+	column := key.column
+	if key.row == 0 {
+		// The number row sits half a key to the left
+		column--
+	}
+	switch {
+	case column <= 0:
+		return "left little"
+	case column == 1:
+		return "left ring"
+	case column == 2:
+		return "left middle"
+	case column <= 4:
+		return "left index"
+	case column <= 6:
+		return "right index"
+	case column == 7:
+		return "right middle"
+	case column == 8:
+		return "right ring"
+	}
+	return "right little"
+}
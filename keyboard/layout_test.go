@@ -0,0 +1,115 @@
+package keyboard
+
+import "testing"
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    *Layout
+		wantErr bool
+	}{
+		{name: "", want: QWERTY},
+		{name: "qwerty", want: QWERTY},
+		{name: "Dvorak", want: Dvorak},
+		{name: "COLEMAK", want: Colemak},
+		{name: "workman", want: Workman},
+		{name: "azerty", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLayout(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLayout(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLayout(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutsMapEveryKeyOnce(t *testing.T) {
+	for _, layout := range Layouts() {
+		t.Run(layout.ID, func(t *testing.T) {
+			mapped := make(map[rune]rune)
+			for r, key := range QWERTY.keys {
+				got := []rune(layout.Remap(string(r)))
+				if len(got) != 1 {
+					t.Fatalf("Remap(%q) = %q, want one character", r, string(got))
+				}
+				if other, taken := mapped[got[0]]; taken {
+					t.Errorf("Remap(%q) and Remap(%q) both type %q", r, other, got[0])
+				}
+				mapped[got[0]] = r
+
+				position, ok := layout.keys[got[0]]
+				if !ok || position != key {
+					t.Errorf("Remap(%q) = %q, which the layout types on another key", r, got[0])
+				}
+			}
+			if len(layout.keys) != len(QWERTY.keys) {
+				t.Errorf("layout has %d characters, want %d", len(layout.keys), len(QWERTY.keys))
+			}
+		})
+	}
+}
+
+func TestRemap(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout *Layout
+		in     string
+		want   string
+	}{
+		{name: "qwerty is unchanged", layout: QWERTY, in: "Hello, world!", want: "Hello, world!"},
+		{name: "dvorak top row", layout: Dvorak, in: "qwerty", want: "',.pyf"},
+		{name: "dvorak keeps shift", layout: Dvorak, in: "QWERTY", want: "\"<>PYF"},
+		{name: "colemak home row", layout: Colemak, in: "jkl;", want: "neio"},
+		{name: "colemak keeps shift", layout: Colemak, in: "JKL:", want: "NEIO"},
+		{name: "workman home row", layout: Workman, in: "asdfghjkl", want: "ashtgyneo"},
+		{name: "unknown runes pass through", layout: Dvorak, in: "é €\n\t", want: "é €\n\t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.layout.Remap(tt.in); got != tt.want {
+				t.Errorf("Remap(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHintFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout *Layout
+		r      rune
+		want   Hint
+		wantOK bool
+	}{
+		{name: "qwerty f", layout: QWERTY, r: 'f', want: Hint{Key: "F key", Finger: "left index", Row: "home row"}, wantOK: true},
+		{name: "qwerty capital a", layout: QWERTY, r: 'A', want: Hint{Key: "A key", Finger: "left little", Row: "home row", Shift: true}, wantOK: true},
+		{name: "qwerty p", layout: QWERTY, r: 'p', want: Hint{Key: "P key", Finger: "right little", Row: "top row"}, wantOK: true},
+		{name: "qwerty 1", layout: QWERTY, r: '1', want: Hint{Key: "1 key", Finger: "left little", Row: "number row"}, wantOK: true},
+		{name: "qwerty 5", layout: QWERTY, r: '5', want: Hint{Key: "5 key", Finger: "left index", Row: "number row"}, wantOK: true},
+		{name: "qwerty m", layout: QWERTY, r: 'm', want: Hint{Key: "M key", Finger: "right index", Row: "bottom row"}, wantOK: true},
+		{name: "dvorak o", layout: Dvorak, r: 'o', want: Hint{Key: "S key", Finger: "left ring", Row: "home row"}, wantOK: true},
+		{name: "colemak capital n", layout: Colemak, r: 'N', want: Hint{Key: "J key", Finger: "right index", Row: "home row", Shift: true}, wantOK: true},
+		{name: "space", layout: Dvorak, r: ' ', want: Hint{Key: "Space", Finger: "thumb"}, wantOK: true},
+		{name: "enter", layout: QWERTY, r: '\n', want: Hint{Key: "Enter", Finger: "right little"}, wantOK: true},
+		{name: "unknown rune", layout: QWERTY, r: 'é'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.layout.HintFor(tt.r)
+			if ok != tt.wantOK {
+				t.Fatalf("HintFor(%q) ok = %v, want %v", tt.r, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("HintFor(%q) = %+v, want %+v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package lessons implements a touch-typing curriculum: lessons that each
+// introduce a few keys, generate text restricted to the keys learned so far
+// and unlock the next lesson once speed and accuracy targets are met.
+package lessons
+
+import (
+	"fmt"
+	"strings"
+
+	"typeracer-tui/keyboard"
+)
+
+// Stage groups lessons by the part of the keyboard they cover
+type Stage string
+
+const (
+	StageHomeRow     Stage = "Home row"
+	StageTopRow      Stage = "Top row"
+	StageBottomRow   Stage = "Bottom row"
+	StageShift       Stage = "Shifted keys"
+	StageNumbers     Stage = "Numbers"
+	StagePunctuation Stage = "Punctuation"
+)
+
+// Lesson introduces a set of keys and the targets needed to pass it
+type Lesson struct {
+	ID    string
+	Stage Stage
+	Name  string
+	// Keys are the characters the lesson introduces
+	Keys string
+	// MinWPM and MinAccuracy must both be reached to pass
+	MinWPM      float64
+	MinAccuracy float64
+}
+
+// Passes reports whether a run meets the lesson's targets
+func (l Lesson) Passes(wpm, accuracy float64) bool {
+	return wpm >= l.MinWPM && accuracy >= l.MinAccuracy
+}
+
+// Title returns the stage and name of the lesson
+func (l Lesson) Title() string {
+	return fmt.Sprintf("%s: %s", l.Stage, l.Name)
+}
+
+// curriculum is every lesson in the order they unlock, with keys as typed
+// on QWERTY
+var curriculum = []Lesson{
+	{ID: "home-left", Stage: StageHomeRow, Name: "Left hand", Keys: "asdf", MinWPM: 10, MinAccuracy: 90},
+	{ID: "home-right", Stage: StageHomeRow, Name: "Right hand", Keys: "jkl;", MinWPM: 10, MinAccuracy: 90},
+	{ID: "home-center", Stage: StageHomeRow, Name: "G and H", Keys: "gh", MinWPM: 12, MinAccuracy: 90},
+	{ID: "top-left", Stage: StageTopRow, Name: "Left hand", Keys: "qwert", MinWPM: 12, MinAccuracy: 92},
+	{ID: "top-right", Stage: StageTopRow, Name: "Right hand", Keys: "yuiop", MinWPM: 15, MinAccuracy: 92},
+	{ID: "bottom-left", Stage: StageBottomRow, Name: "Left hand", Keys: "zxcvb", MinWPM: 15, MinAccuracy: 92},
+	{ID: "bottom-right", Stage: StageBottomRow, Name: "Right hand", Keys: "nm,./", MinWPM: 18, MinAccuracy: 94},
+	{ID: "shift", Stage: StageShift, Name: "Capital letters", Keys: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", MinWPM: 18, MinAccuracy: 94},
+	{ID: "numbers", Stage: StageNumbers, Name: "Number row", Keys: "1234567890", MinWPM: 15, MinAccuracy: 92},
+	{ID: "punctuation", Stage: StagePunctuation, Name: "Quotes, brackets and marks", Keys: "'\"-?!:()", MinWPM: 18, MinAccuracy: 94},
+}
+
+// Curriculum returns every lesson in the order they unlock
+func Curriculum() []Lesson {
+	return CurriculumFor(keyboard.QWERTY)
+}
+
+// CurriculumFor returns the lessons for a keyboard layout. Lessons cover
+// the same key positions on every layout, so the home row lesson of Dvorak
+// teaches "aoeu" where QWERTY teaches "asdf". Progress is kept separately
+// for each layout.
+func CurriculumFor(layout *keyboard.Layout) []Lesson {
+	lessons := make([]Lesson, len(curriculum))
+	copy(lessons, curriculum)
+	if layout.IsQWERTY() {
+		return lessons
+	}
+
+	for i := range lessons {
+		lessons[i].ID = layout.ID + ":" + lessons[i].ID
+		lessons[i].Keys = layout.Remap(lessons[i].Keys)
+	}
+	return lessons
+}
+
+// AllowedKeys returns every key learned up to and including the lesson at
+// index, which is what its practice text may use
+func AllowedKeys(lessons []Lesson, index int) string {
+	var keys strings.Builder
+	for i := 0; i <= index && i < len(lessons); i++ {
+		keys.WriteString(lessons[i].Keys)
+	}
+	return keys.String()
+}
@@ -0,0 +1,166 @@
+package lessons
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"unicode"
+
+	"typeracer-tui/quotes"
+)
+
+// Text generation tuning
+const (
+	// minRealWords is how many dictionary words must fit the allowed keys
+	// before real words are used instead of letter drills
+	minRealWords = 10
+	// focusShare is how often a token is chosen to practise the lesson's
+	// own keys rather than any key learned so far
+	focusShare = 0.7
+)
+
+// Generate returns practice text of roughly length characters that only
+// uses the allowed keys, favouring the focus keys the lesson introduces.
+// Real words are used once enough of them can be typed; before that the
+// text is made of short letter drills.
+func Generate(allowed, focus string, length int, rng *rand.Rand) string {
+	keys := newKeySet(allowed)
+	focusKeys := newKeySet(focus)
+
+	words := wordsFrom(keys.lower)
+	var focusWords []string
+	for _, word := range words {
+		if strings.ContainsAny(word, string(focusKeys.lower)) {
+			focusWords = append(focusWords, word)
+		}
+	}
+
+	var tokens []string
+	total := 0
+	for total < length {
+		var token string
+		switch {
+		case len(words) >= minRealWords && len(focusWords) > 0 && rng.Float64() < focusShare:
+			token = focusWords[rng.Intn(len(focusWords))]
+		case len(words) >= minRealWords:
+			token = words[rng.Intn(len(words))]
+		default:
+			token = drill(keys.lower, focusKeys.lower, rng)
+		}
+		token = decorate(token, keys, focusKeys, rng)
+
+		tokens = append(tokens, token)
+		total += len(token) + 1
+	}
+	return strings.Join(tokens, " ")
+}
+
+// keySet sorts keys into the kinds of character text is built from
+type keySet struct {
+	lower       []rune
+	upper       map[rune]bool
+	digits      []rune
+	punctuation map[rune]bool
+}
+
+// newKeySet classifies the given keys
+func newKeySet(keys string) keySet {
+	set := keySet{upper: make(map[rune]bool), punctuation: make(map[rune]bool)}
+	for _, key := range keys {
+		switch {
+		case unicode.IsLower(key):
+			set.lower = append(set.lower, key)
+		case unicode.IsUpper(key):
+			set.upper[key] = true
+		case unicode.IsDigit(key):
+			set.digits = append(set.digits, key)
+		case key != ' ':
+			set.punctuation[key] = true
+		}
+	}
+	return set
+}
+
+// wordsFrom returns the common English words spelled only with letters
+func wordsFrom(letters []rune) []string {
+	var words []string
+	for _, word := range quotes.EnglishWords(quotes.Words5k) {
+		if strings.Trim(word, string(letters)) == "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// drill returns a short made-up group of letters, about half of them
+// focus letters
+func drill(letters, focus []rune, rng *rand.Rand) string {
+	n := 2 + rng.Intn(4)
+	drill := make([]rune, n)
+	for i := range drill {
+		if len(focus) > 0 && rng.Intn(2) == 0 {
+			drill[i] = focus[rng.Intn(len(focus))]
+		} else {
+			drill[i] = letters[rng.Intn(len(letters))]
+		}
+	}
+	return string(drill)
+}
+
+// decorate mixes capitals, numbers and punctuation into a token when the
+// keys allow them, much more often for keys the lesson is about
+func decorate(token string, keys, focus keySet, rng *rand.Rand) string {
+	chance := func(inFocus bool) bool {
+		if inFocus {
+			return rng.Float64() < 0.4
+		}
+		return rng.Float64() < 0.08
+	}
+
+	if len(keys.digits) > 0 && chance(len(focus.digits) > 0) {
+		number := make([]rune, 1+rng.Intn(4))
+		for i := range number {
+			number[i] = keys.digits[rng.Intn(len(keys.digits))]
+		}
+		token = string(number)
+	}
+
+	if first := []rune(token)[0]; keys.upper[unicode.ToUpper(first)] && chance(focus.upper[unicode.ToUpper(first)]) {
+		token = string(unicode.ToUpper(first)) + token[len(string(first)):]
+	}
+
+	marks := sortedKeys(keys.punctuation)
+	if focusMarks := sortedKeys(focus.punctuation); len(focusMarks) > 0 && rng.Float64() < focusShare {
+		marks = focusMarks
+	}
+	if len(marks) == 0 {
+		return token
+	}
+	mark := marks[rng.Intn(len(marks))]
+	if !chance(focus.punctuation[mark]) {
+		return token
+	}
+
+	switch mark {
+	case '"', '\'':
+		return string(mark) + token + string(mark)
+	case '(', ')':
+		if keys.punctuation['('] && keys.punctuation[')'] {
+			return "(" + token + ")"
+		}
+		return token
+	case '-', '/':
+		return token + string(mark) + drill(keys.lower, focus.lower, rng)
+	}
+	return token + string(mark)
+}
+
+// sortedKeys returns the keys of a set in a stable order
+func sortedKeys(set map[rune]bool) []rune {
+	keys := make([]rune, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,29 @@
+package lessons
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"typeracer-tui/keyboard"
+)
+
+func TestGenerateUsesOnlyAllowedKeys(t *testing.T) {
+	for _, layout := range keyboard.Layouts() {
+		lessons := CurriculumFor(layout)
+		for i, lesson := range lessons {
+			t.Run(layout.ID+"/"+lesson.ID, func(t *testing.T) {
+				allowed := AllowedKeys(lessons, i)
+				rng := rand.New(rand.NewSource(int64(i)))
+				for run := 0; run < 20; run++ {
+					text := Generate(allowed, lesson.Keys, 200, rng)
+					for _, r := range text {
+						if r != ' ' && !strings.ContainsRune(allowed, r) {
+							t.Fatalf("Generate() typed %q, which is not among %q: %q", r, allowed, text)
+						}
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package lessons
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"typeracer-tui/quotes"
+)
+
+// Record is a player's best results on one lesson
+type Record struct {
+	Attempts     int       `json:"attempts"`
+	BestWPM      float64   `json:"best_wpm"`
+	BestAccuracy float64   `json:"best_accuracy"`
+	Passed       bool      `json:"passed"`
+	PassedAt     time.Time `json:"passed_at,omitempty"`
+}
+
+// Progress stores lesson results in a JSON file
+type Progress struct {
+	path    string
+	lessons map[string]*Record
+	mu      sync.RWMutex
+}
+
+// DefaultProgressPath returns the lesson progress file in the user's config
+// directory
+func DefaultProgressPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "typeracer-tui", "lessons.json"), nil
+}
+
+// NewProgress creates progress kept only in memory
+func NewProgress() *Progress {
+	return &Progress{lessons: make(map[string]*Record)}
+}
+
+// OpenProgress loads progress from path; a missing file means no lesson has
+// been tried yet
+func OpenProgress(path string) (*Progress, error) {
+	progress := NewProgress()
+	progress.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lesson progress: %w", err)
+	}
+	if err := json.Unmarshal(data, &progress.lessons); err != nil {
+		return nil, fmt.Errorf("failed to parse lesson progress: %w", err)
+	}
+	return progress, nil
+}
+
+// Get returns the results recorded for a lesson
+func (p *Progress) Get(lessonID string) Record {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if record, exists := p.lessons[lessonID]; exists {
+		return *record
+	}
+	return Record{}
+}
+
+// Unlocked reports whether the lesson at index may be started: the first
+// lesson always can, later ones once the lesson before has been passed
+func (p *Progress) Unlocked(lessons []Lesson, index int) bool {
+	if index <= 0 {
+		return true
+	}
+	return p.Get(lessons[index-1].ID).Passed
+}
+
+// Record saves a run through a lesson and reports whether it passed
+func (p *Progress) Record(lesson Lesson, wpm, accuracy float64) (bool, error) {
+	passed := lesson.Passes(wpm, accuracy)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	record, exists := p.lessons[lesson.ID]
+	if !exists {
+		record = &Record{}
+		p.lessons[lesson.ID] = record
+	}
+	record.Attempts++
+	record.BestWPM = max(record.BestWPM, wpm)
+	record.BestAccuracy = max(record.BestAccuracy, accuracy)
+	if passed && !record.Passed {
+		record.Passed = true
+		record.PassedAt = time.Now()
+	}
+
+	if p.path == "" {
+		return passed, nil
+	}
+	if err := quotes.WriteJSONFile(p.path, p.lessons); err != nil {
+		return passed, fmt.Errorf("failed to write lesson progress: %w", err)
+	}
+	return passed, nil
+}
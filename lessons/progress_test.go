@@ -0,0 +1,81 @@
+package lessons
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProgressUnlocksOnBothTargets(t *testing.T) {
+	lessons := Curriculum()
+	first := lessons[0]
+
+	tests := []struct {
+		name     string
+		wpm      float64
+		accuracy float64
+		want     bool
+	}{
+		{name: "both targets met", wpm: first.MinWPM, accuracy: first.MinAccuracy, want: true},
+		{name: "too slow", wpm: first.MinWPM - 1, accuracy: 100},
+		{name: "too inaccurate", wpm: first.MinWPM * 3, accuracy: first.MinAccuracy - 1},
+		{name: "neither", wpm: 1, accuracy: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := NewProgress()
+			passed, err := progress.Record(first, tt.wpm, tt.accuracy)
+			if err != nil {
+				t.Fatalf("Record() error: %v", err)
+			}
+			if passed != tt.want {
+				t.Errorf("Record() passed = %v, want %v", passed, tt.want)
+			}
+			if got := progress.Unlocked(lessons, 1); got != tt.want {
+				t.Errorf("Unlocked(1) = %v, want %v", got, tt.want)
+			}
+			if !progress.Unlocked(lessons, 0) {
+				t.Error("the first lesson is locked")
+			}
+		})
+	}
+}
+
+func TestProgressRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lessons.json")
+	lessons := Curriculum()
+
+	progress, err := OpenProgress(path)
+	if err != nil {
+		t.Fatalf("OpenProgress() error: %v", err)
+	}
+	runs := []struct {
+		lesson        Lesson
+		wpm, accuracy float64
+	}{
+		{lessons[0], 8, 95},
+		{lessons[0], 25, 97.5},
+		{lessons[1], 12, 80},
+	}
+	for _, run := range runs {
+		if _, err := progress.Record(run.lesson, run.wpm, run.accuracy); err != nil {
+			t.Fatalf("Record() error: %v", err)
+		}
+	}
+
+	reopened, err := OpenProgress(path)
+	if err != nil {
+		t.Fatalf("OpenProgress() error: %v", err)
+	}
+	for _, lesson := range lessons[:3] {
+		want, got := progress.Get(lesson.ID), reopened.Get(lesson.ID)
+		if got.Attempts != want.Attempts || got.BestWPM != want.BestWPM ||
+			got.BestAccuracy != want.BestAccuracy || got.Passed != want.Passed ||
+			!got.PassedAt.Equal(want.PassedAt) {
+			t.Errorf("%s reloaded as %+v, want %+v", lesson.ID, got, want)
+		}
+	}
+	if got := reopened.Get(lessons[0].ID); got.Attempts != 2 || !got.Passed {
+		t.Errorf("%s = %+v, want two attempts and a pass", lessons[0].ID, got)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"typeracer-tui/quotes"
+)
+
+// libraryUsage describes the library subcommands
+const libraryUsage = `Usage: typeracer-tui library <command> [flags] [args]
+
+Manage the local quote library used by -quotes library (the default).
+Until the first change it holds a copy of the embedded quotes.
+
+Commands:
+  list [-length L] [-difficulty D] [-tags T] [-author A] [-language LANG]
+                          List quotes, optionally filtered
+  search QUERY            Find quotes whose text, author, tags or ID contain every word
+  add [-author A] [-tags T] [-language LANG] TEXT
+                          Add a quote; TEXT '-' reads it from standard input
+  import FILE...          Add quotes from .json or .csv files (content, author, tags,
+                          language columns)
+  packs [LANG...]         List the embedded language packs, or add them to the library
+  export [-format F] [FILE]
+                          Write the library as json or csv, to standard output without FILE
+  dedupe [-dry-run]       Remove quotes whose text repeats an earlier one
+  delete ID...            Remove quotes by ID
+  path                    Print where the library is saved
+`
+
+// runLibraryCommand runs a library subcommand
+func runLibraryCommand(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		fmt.Print(libraryUsage)
+		return nil
+	}
+
+	path, err := quotes.DefaultLibraryPath()
+	if err != nil {
+		return err
+	}
+	store, err := quotes.OpenLibraryStore(path)
+	if err != nil {
+		return err
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "list":
+		return libraryList(store, args)
+	case "search":
+		if len(args) == 0 {
+			return fmt.Errorf("search needs a query")
+		}
+		printQuotes(store.Search(strings.Join(args, " ")))
+		return nil
+	case "add":
+		return libraryAdd(store, args)
+	case "import":
+		return libraryImport(store, args)
+	case "export":
+		return libraryExport(store, args)
+	case "dedupe":
+		return libraryDedupe(store, args)
+	case "packs":
+		return libraryPacks(store, args)
+	case "delete":
+		if len(args) == 0 {
+			return fmt.Errorf("delete needs at least one quote ID")
+		}
+		deleted, err := store.Delete(args...)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %d quotes.\n", deleted)
+		return store.Save()
+	case "path":
+		fmt.Println(store.Path())
+		return nil
+	}
+	return fmt.Errorf("unknown library command %q; run 'typeracer-tui library help'", command)
+}
+
+// libraryList prints the quotes matching the given filter flags
+func libraryList(store *quotes.LibraryStore, args []string) error {
+	flags := flag.NewFlagSet("library list", flag.ContinueOnError)
+	length := flags.String("length", "", "Quote length: 'short', 'medium' or 'long'")
+	difficulty := flags.String("difficulty", "", "Quote difficulty: 'easy', 'medium' or 'hard'")
+	tags := flags.String("tags", "", "Comma-separated quote tags")
+	author := flags.String("author", "", "Part of the author's name")
+	language := flags.String("language", "", "Quote language, e.g. 'en' or 'de'")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	filter, err := parseQuoteFilter(*length, *difficulty, *tags, *language)
+	if err != nil {
+		return err
+	}
+
+	var matches []quotes.Quote
+	for _, quote := range store.Library().Select(filter) {
+		if strings.Contains(strings.ToLower(quote.Author), strings.ToLower(*author)) {
+			matches = append(matches, quote)
+		}
+	}
+	printQuotes(matches)
+	return nil
+}
+
+// libraryAdd adds one quote from the command line or standard input
+func libraryAdd(store *quotes.LibraryStore, args []string) error {
+	flags := flag.NewFlagSet("library add", flag.ContinueOnError)
+	author := flags.String("author", "", "Who said or wrote the quote")
+	tags := flags.String("tags", "", "Comma-separated quote tags")
+	language := flags.String("language", "", "Language the quote is written in (default: en)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	lang, err := quotes.ParseLanguage(*language)
+	if err != nil {
+		return err
+	}
+
+	text := strings.Join(flags.Args(), " ")
+	if text == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read quote: %w", err)
+		}
+		text = strings.Join(strings.Fields(string(data)), " ")
+	}
+
+	quote, err := store.Add(quotes.Quote{
+		Content:  text,
+		Author:   *author,
+		Tags:     strings.Split(*tags, ","),
+		Language: lang,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added %s.\n", quote.ID)
+	return store.Save()
+}
+
+// libraryImport adds the quotes from JSON and CSV files
+func libraryImport(store *quotes.LibraryStore, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("import needs at least one file")
+	}
+
+	for _, path := range args {
+		loaded, err := quotes.ReadQuotesFile(path)
+		if err != nil {
+			return err
+		}
+		added, skipped := store.Import(loaded)
+		fmt.Printf("%s: added %d quotes, skipped %d empty or already present.\n", path, added, skipped)
+	}
+	return store.Save()
+}
+
+// libraryExport writes the library as JSON or CSV
+func libraryExport(store *quotes.LibraryStore, args []string) error {
+	flags := flag.NewFlagSet("library export", flag.ContinueOnError)
+	format := flags.String("format", "", "Output format: 'json' or 'csv' (default: from the file extension, else json)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	var write func(io.Writer, []quotes.Quote) error
+	switch *format {
+	case "", "json":
+		write = quotes.WriteQuotesJSON
+	case "csv":
+		write = quotes.WriteQuotesCSV
+	default:
+		return fmt.Errorf("unknown export format %q (want json or csv)", *format)
+	}
+
+	all := store.Library().All()
+	if path == "" {
+		return write(os.Stdout, all)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(file, all); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	// Closing flushes the export, so its error means the file is incomplete
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// libraryPacks lists the embedded language packs, or adds the named ones to
+// a library saved before they were available
+func libraryPacks(store *quotes.LibraryStore, args []string) error {
+	if len(args) == 0 {
+		for _, language := range quotes.Languages() {
+			fmt.Printf("%s  %-10s %d quotes\n", language, language.Label(), len(quotes.Pack(language)))
+		}
+		return nil
+	}
+
+	for _, arg := range args {
+		language, err := quotes.ParseLanguage(arg)
+		if err != nil {
+			return err
+		}
+		added, skipped := store.Import(quotes.Pack(language))
+		fmt.Printf("%s: added %d quotes, skipped %d already present.\n", language.Label(), added, skipped)
+	}
+	return store.Save()
+}
+
+// libraryDedupe removes repeated quotes
+func libraryDedupe(store *quotes.LibraryStore, args []string) error {
+	flags := flag.NewFlagSet("library dedupe", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "List the duplicates without removing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	removed := store.Dedupe()
+	printQuotes(removed)
+	if *dryRun {
+		fmt.Printf("%d duplicate quotes would be removed.\n", len(removed))
+		return nil
+	}
+	fmt.Printf("Removed %d duplicate quotes.\n", len(removed))
+	if len(removed) == 0 {
+		return nil
+	}
+	return store.Save()
+}
+
+// printQuotes prints one line per quote with its ID, tags and a preview
+func printQuotes(list []quotes.Quote) {
+	for _, quote := range list {
+		text := []rune(strings.Join(strings.Fields(quote.Content), " "))
+		if len(text) > 60 {
+			text = append(text[:57], []rune("...")...)
+		}
+		line := fmt.Sprintf("%s  \"%s\" — %s", quote.ID, string(text), quote.Author)
+		if language := quotes.LanguageOf(&quote); language != quotes.DefaultLanguage {
+			line += " (" + string(language) + ")"
+		}
+		if len(quote.Tags) > 0 {
+			line += " [" + strings.Join(quote.Tags, ", ") + "]"
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("%d quotes\n", len(list))
+}
@@ -1,22 +1,68 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"math"
+	"net/http"
+	"os"
+	"os/signal"
+	"os/user"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"typeracer-tui/game"
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/keyboard"
+	"typeracer-tui/lessons"
+	"typeracer-tui/quotes"
 	"typeracer-tui/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	// Library management runs as a subcommand with its own flags
+	if len(os.Args) > 1 && os.Args[1] == "library" {
+		if err := runLibraryCommand(os.Args[2:]); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		return
+	}
+
 	// Parse command line flags
 	var (
-		mode    = flag.String("mode", "practice", "Mode: 'practice' or 'server'")
-		port    = flag.String("port", "2222", "SSH server port (server mode only)")
-		players = flag.Int("players", 4, "Maximum players per room (server mode only)")
-		help    = flag.Bool("help", false, "Show help")
+		mode       = flag.String("mode", "practice", "Mode: 'practice', 'lessons', 'server' or 'quotes'")
+		port       = flag.String("port", "2222", "SSH server port, or HTTP port for quotes mode (default 8080)")
+		players    = flag.Int("players", 4, "Maximum players per room (server mode only)")
+		prefetch   = flag.Int("prefetch", game.DefaultPrefetchSize, "Quotes kept ready per difficulty (server mode only)")
+		length     = flag.String("length", "", "Quote length: 'short', 'medium' or 'long'")
+		difficulty = flag.String("difficulty", "", "Quote difficulty: 'easy', 'medium' or 'hard'")
+		tags       = flag.String("tags", "", "Comma-separated quote tags to draw from")
+		language   = flag.String("language", "en", "Language to race in: 'en', 'de', 'es', 'fr', 'ru' or any language code")
+		source     = flag.String("quotes", "library", "Quote sources: 'library', 'embedded', 'api', 'dir:PATH', 'code:PATH', 'words[:SIZE]', 'symbols[:PERCENT]', optionally weighted and comma-separated")
+		lowercase  = flag.Bool("lowercase", false, "Convert quotes to lower case")
+		noPunct    = flag.Bool("no-punctuation", false, "Strip punctuation from quotes")
+		ascii      = flag.Bool("ascii", false, "Replace accented letters with plain ASCII")
+		text       = flag.String("text", "", "Practice on a text file, or '-' for standard input (practice mode only)")
+		book       = flag.String("book", "", "Type through a plain-text or EPUB book chapter by chapter (practice mode only)")
+		fromStart  = flag.Bool("from-start", false, "Start -text or -book from the beginning instead of the saved position")
+		listMarks  = flag.Bool("bookmarks", false, "Show your progress through saved texts and books")
+		resetSeen  = flag.Bool("reset-history", false, "Forget which quotes have been typed so they can come up again")
+		showBoard  = flag.Bool("leaderboard", false, "Show the top times and average WPM for the most typed quotes")
+		apiURL     = flag.String("api-url", quotes.DefaultAPIURL, "Base URL of the quotable-compatible API used by -quotes api")
+		apiTimeout = flag.Duration("api-timeout", quotes.DefaultFetcherOptions().Timeout, "Timeout for each request to the quote API")
+		adminKeys  = flag.String("admin-keys", "", "authorized_keys file of players who may review quote submissions (server mode only)")
+		community  = flag.Float64("community", 0.2, "Share of races drawn from approved player submissions (server mode only)")
+		layoutName = flag.String("layout", "qwerty", "Keyboard layout to emulate: 'qwerty', 'dvorak', 'colemak' or 'workman'")
+		metricName = flag.String("metric", "net", "Speed shown as the headline: 'net' or 'gross' WPM, or raw 'cpm'")
+		help       = flag.Bool("help", false, "Show help")
 	)
 	flag.Parse()
 
@@ -25,33 +71,486 @@ func main() {
 		return
 	}
 
+	if *showBoard {
+		if err := showLeaderboard(); err != nil {
+			log.Fatalf("Error reading leaderboard: %v", err)
+		}
+		return
+	}
+
+	if *listMarks {
+		if err := showBookmarks(); err != nil {
+			log.Fatalf("Error reading bookmarks: %v", err)
+		}
+		return
+	}
+
+	filter, err := parseQuoteFilter(*length, *difficulty, *tags, *language)
+	if err != nil {
+		log.Fatalf("Invalid quote filter: %v", err)
+	}
+
+	layout, err := keyboard.ParseLayout(*layoutName)
+	if err != nil {
+		log.Fatalf("Invalid layout: %v", err)
+	}
+
+	metric, err := scoring.ParseMetric(*metricName)
+	if err != nil {
+		log.Fatalf("Invalid metric: %v", err)
+	}
+
+	fetcherOptions := quotes.DefaultFetcherOptions()
+	fetcherOptions.BaseURL = *apiURL
+	fetcherOptions.Timeout = *apiTimeout
+	if *mode == "server" {
+		fetcherOptions.OnHealthChange = logAPIHealth
+	}
+
+	quoteSource, err := parseQuoteSource(*source, fetcherOptions)
+	if err != nil {
+		log.Fatalf("Invalid quote source: %v", err)
+	}
+
+	normalizer := quotes.NewNormalizer(quotes.NormalizeOptions{
+		FoldTypography:     true,
+		FoldDiacritics:     *ascii,
+		CollapseWhitespace: true,
+		Lowercase:          *lowercase,
+		StripPunctuation:   *noPunct,
+	})
+
+	history := openHistory(*resetSeen)
+	leaderboard := openLeaderboard()
+	ratings := openRatings()
+
 	switch *mode {
 	case "practice":
-		runPracticeMode()
+		if *text != "" || *book != "" {
+			document, err := loadDocument(*text, *book, filter.Length, *fromStart)
+			if err != nil {
+				log.Fatalf("Invalid text: %v", err)
+			}
+			quoteSource = document
+		}
+		runPracticeMode(quoteSource, filter, normalizer, history, leaderboard, ratings, layout, metric, *text == "-")
+	case "lessons":
+		runLessonsMode(layout, metric)
 	case "server":
-		runServerMode(*port, *players)
+		runServerMode(*port, *players, *prefetch, quoteSource, filter, normalizer, history, leaderboard, ratings, *adminKeys, *community)
+	case "quotes":
+		if !flagSet("port") {
+			*port = defaultQuoteServerPort
+		}
+		runQuoteServerMode(*port, quoteSource)
 	default:
-		log.Fatalf("Invalid mode: %s. Use 'practice' or 'server'", *mode)
+		log.Fatalf("Invalid mode: %s. Use 'practice', 'lessons', 'server' or 'quotes'", *mode)
+	}
+}
+
+// parseQuoteFilter builds a quote filter from command line flags
+func parseQuoteFilter(length, difficulty, tags, language string) (quotes.Filter, error) {
+	var filter quotes.Filter
+	var err error
+
+	if filter.Length, err = quotes.ParseLengthBucket(length); err != nil {
+		return filter, err
+	}
+	if filter.Difficulty, err = quotes.ParseDifficulty(difficulty); err != nil {
+		return filter, err
+	}
+	if filter.Language, err = quotes.ParseLanguage(language); err != nil {
+		return filter, err
+	}
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseQuoteSource builds a quote source from a spec such as
+// "embedded=3,api=1,dir:/srv/quotes=2"; the API is reached with
+// fetcherOptions
+func parseQuoteSource(spec string, fetcherOptions quotes.FetcherOptions) (quotes.Source, error) {
+	weighted := quotes.NewWeightedSource()
+	count := 0
+	var single quotes.Source
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// Only a trailing =NUMBER is a weight, so dir: paths may contain '='
+		name, weight := part, 1.0
+		if i := strings.LastIndex(part, "="); i >= 0 {
+			if w, err := strconv.ParseFloat(part[i+1:], 64); err == nil {
+				if w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+					return nil, fmt.Errorf("invalid weight in %q", part)
+				}
+				name, weight = part[:i], w
+			}
+		}
+
+		var source quotes.Source
+		switch {
+		case name == "library":
+			library, err := quotes.LocalLibrary()
+			if err != nil {
+				return nil, err
+			}
+			source = library
+		case name == "embedded":
+			source = quotes.DefaultLibrary()
+		case name == "api":
+			source = quotes.NewFetcherWithOptions(fetcherOptions)
+		case strings.HasPrefix(name, "dir:"):
+			library, err := quotes.LoadDir(strings.TrimPrefix(name, "dir:"))
+			if err != nil {
+				return nil, err
+			}
+			source = library
+		case name == "words" || strings.HasPrefix(name, "words:"):
+			options, err := parseWordOptions(strings.TrimPrefix(strings.TrimPrefix(name, "words"), ":"))
+			if err != nil {
+				return nil, err
+			}
+			source = quotes.NewWordSource(options)
+		case name == "symbols" || strings.HasPrefix(name, "symbols:"):
+			density, err := quotes.ParseSymbolDensity(strings.TrimPrefix(strings.TrimPrefix(name, "symbols"), ":"))
+			if err != nil {
+				return nil, err
+			}
+			source = quotes.NewSymbolSource(quotes.SymbolOptions{Density: density})
+		case strings.HasPrefix(name, "code:"):
+			code, err := quotes.NewCodeSource(strings.TrimPrefix(name, "code:"))
+			if err != nil {
+				return nil, err
+			}
+			source = code
+		default:
+			return nil, fmt.Errorf("unknown quote source %q", name)
+		}
+
+		weighted.Add(source, weight)
+		single = source
+		count++
+	}
+
+	switch count {
+	case 0:
+		return quotes.DefaultSource(), nil
+	case 1:
+		// A lone source is used directly so it can be searched exhaustively
+		return single, nil
 	}
+	return weighted, nil
 }
 
-// runPracticeMode runs the single-player practice mode
-func runPracticeMode() {
+// logAPIHealth reports the quote API going down or coming back
+func logAPIHealth(health quotes.SourceHealth) {
+	switch health.State {
+	case quotes.BreakerOpen:
+		log.Printf("Quote API %s is down after %d failed requests (%s); using other sources until %s",
+			health.Name, health.ConsecutiveFailures, health.LastError, health.RetryAt.Format(time.TimeOnly))
+	case quotes.BreakerHalfOpen:
+		log.Printf("Quote API %s: trying again", health.Name)
+	case quotes.BreakerClosed:
+		log.Printf("Quote API %s is back up", health.Name)
+	}
+}
+
+// parseWordOptions parses a word list spec such as "200" or
+// "1k+punctuation+numbers"
+func parseWordOptions(spec string) (quotes.WordOptions, error) {
+	var options quotes.WordOptions
+	parts := strings.Split(spec, "+")
+
+	size, err := quotes.ParseWordListSize(parts[0])
+	if err != nil {
+		return options, err
+	}
+	options.Size = size
+
+	for _, modifier := range parts[1:] {
+		switch modifier {
+		case "punctuation":
+			options.Punctuation = true
+		case "numbers":
+			options.Numbers = true
+		default:
+			return options, fmt.Errorf("unknown word list option %q (want punctuation or numbers)", modifier)
+		}
+	}
+	return options, nil
+}
+
+// openHistory opens the record of typed quotes, optionally clearing it.
+// Without it quotes are still served, just with possible repeats.
+func openHistory(reset bool) *quotes.History {
+	path, err := quotes.DefaultHistoryPath()
+	if err != nil {
+		log.Printf("Warning: quote history will not be saved: %v", err)
+		return quotes.NewHistory()
+	}
+
+	history, err := quotes.OpenHistory(path)
+	if err != nil {
+		log.Printf("Warning: quote history will not be saved: %v", err)
+		return quotes.NewHistory()
+	}
+
+	if reset {
+		if err := history.ResetAll(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	return history
+}
+
+// openLeaderboard opens the per-quote results. Without it results are
+// still shown for the current run, just not kept.
+func openLeaderboard() *quotes.Leaderboard {
+	path, err := quotes.DefaultLeaderboardPath()
+	if err != nil {
+		log.Printf("Warning: results will not be saved: %v", err)
+		return quotes.NewLeaderboard()
+	}
+
+	leaderboard, err := quotes.OpenLeaderboard(path)
+	if err != nil {
+		log.Printf("Warning: results will not be saved: %v", err)
+		return quotes.NewLeaderboard()
+	}
+	return leaderboard
+}
+
+// openRatings opens the votes cast on quotes. Without it votes still
+// count for the current run, just not kept.
+func openRatings() *quotes.Ratings {
+	path, err := quotes.DefaultRatingsPath()
+	if err != nil {
+		log.Printf("Warning: quote ratings will not be saved: %v", err)
+		return quotes.NewRatings()
+	}
+
+	ratings, err := quotes.OpenRatings(path)
+	if err != nil {
+		log.Printf("Warning: quote ratings will not be saved: %v", err)
+		return quotes.NewRatings()
+	}
+	return ratings
+}
+
+// openSubmissions opens the queue of player-submitted quotes. Without it
+// submissions still work until the server stops, just not kept.
+func openSubmissions() *quotes.SubmissionQueue {
+	path, err := quotes.DefaultSubmissionsPath()
+	if err != nil {
+		log.Printf("Warning: quote submissions will not be saved: %v", err)
+		return quotes.NewSubmissionQueue()
+	}
+
+	submissions, err := quotes.OpenSubmissionQueue(path)
+	if err != nil {
+		log.Printf("Warning: quote submissions will not be saved: %v", err)
+		return quotes.NewSubmissionQueue()
+	}
+	return submissions
+}
+
+// showLeaderboard prints the top times for the most typed quotes
+func showLeaderboard() error {
+	path, err := quotes.DefaultLeaderboardPath()
+	if err != nil {
+		return err
+	}
+	leaderboard, err := quotes.OpenLeaderboard(path)
+	if err != nil {
+		return err
+	}
+
+	most := leaderboard.MostRaced(20)
+	if len(most) == 0 {
+		fmt.Println("No results recorded yet.")
+		return nil
+	}
+
+	for _, stats := range most {
+		fmt.Printf("%s  \"%s\" — %s\n", stats.QuoteID, stats.Preview, stats.Author)
+		fmt.Printf("    typed %d times, average %.1f WPM\n", stats.Races, stats.AverageWPM())
+		for i, result := range stats.Top {
+			if i == 3 {
+				break
+			}
+			fmt.Printf("    %d. %-20s %6.1fs %6.1f WPM  (%s)\n", i+1, result.Player, result.Seconds, result.WPM, result.Mode)
+		}
+	}
+	return nil
+}
+
+// practiceName returns the name practice results are recorded under
+func practiceName() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return "you"
+}
+
+// openBookmarks opens the bookmark store in the user's config directory
+func openBookmarks() (*quotes.BookmarkStore, error) {
+	path, err := quotes.DefaultBookmarkPath()
+	if err != nil {
+		return nil, err
+	}
+	return quotes.OpenBookmarkStore(path)
+}
+
+// loadDocument opens a text file or book for practice, resuming from the
+// saved bookmark unless fromStart is set
+func loadDocument(textPath, bookPath string, length quotes.LengthBucket, fromStart bool) (*quotes.DocumentSource, error) {
+	bookmarks, err := openBookmarks()
+	if err != nil {
+		log.Printf("Warning: positions will not be saved: %v", err)
+		bookmarks = nil
+	}
+
+	var source *quotes.DocumentSource
+	if bookPath != "" {
+		source, err = quotes.LoadBook(bookPath, quotes.PassageLength(length), bookmarks)
+	} else {
+		source, err = quotes.LoadTextFile(textPath, quotes.PassageLength(length), bookmarks)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fromStart {
+		if err := source.Reset(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	return source, nil
+}
+
+// showBookmarks prints the progress through every saved text and book
+func showBookmarks() error {
+	bookmarks, err := openBookmarks()
+	if err != nil {
+		return err
+	}
+
+	all := bookmarks.All()
+	if len(all) == 0 {
+		fmt.Println("No saved positions yet. Practice with -text or -book to create one.")
+		return nil
+	}
+
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return all[keys[i]].UpdatedAt.After(all[keys[j]].UpdatedAt)
+	})
+
+	for _, key := range keys {
+		bookmark := all[key]
+		position := fmt.Sprintf("passage %d/%d", bookmark.Done+1, bookmark.Total)
+		if bookmark.Chapters > 1 {
+			position = fmt.Sprintf("chapter %d/%d, %s", bookmark.Chapter+1, bookmark.Chapters, position)
+		}
+		fmt.Printf("%-40s %5.1f%%  %s  (last read %s)\n",
+			bookmark.Name, bookmark.Percent(), position, bookmark.UpdatedAt.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// runPracticeMode runs the single-player practice mode. When the text came
+// from standard input, keys are read from the terminal instead.
+func runPracticeMode(source quotes.Source, filter quotes.Filter, normalizer *quotes.Normalizer, history *quotes.History, leaderboard *quotes.Leaderboard, ratings *quotes.Ratings, layout *keyboard.Layout, metric scoring.Metric, stdinText bool) {
 	fmt.Println("Starting TypeRacer Practice Mode...")
 
-	model := ui.NewPracticeModel()
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	model := ui.NewPracticeModelWithSource(source, filter)
+	model.SetNormalizer(normalizer)
+	model.SetHistory(history)
+	model.SetLeaderboard(leaderboard, practiceName())
+	model.SetRatings(ratings)
+	model.SetLayout(layout)
+	model.SetMetric(metric)
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if stdinText {
+		options = append(options, tea.WithInputTTY())
+	}
+	program := tea.NewProgram(model, options...)
 
 	if err := program.Start(); err != nil {
 		log.Fatalf("Error running practice mode: %v", err)
 	}
 }
 
+// runLessonsMode runs the touch-typing course for a keyboard layout,
+// headlining metric while typing
+func runLessonsMode(layout *keyboard.Layout, metric scoring.Metric) {
+	fmt.Println("Starting TypeRacer Lessons...")
+
+	model := ui.NewLessonsModel(openLessonProgress(), layout)
+	model.SetMetric(metric)
+	program := tea.NewProgram(model, tea.WithAltScreen())
+	if err := program.Start(); err != nil {
+		log.Fatalf("Error running lessons: %v", err)
+	}
+}
+
+// openLessonProgress opens the record of passed lessons. Without it lessons
+// still unlock for the current run, just not kept.
+func openLessonProgress() *lessons.Progress {
+	path, err := lessons.DefaultProgressPath()
+	if err != nil {
+		log.Printf("Warning: lesson progress will not be saved: %v", err)
+		return lessons.NewProgress()
+	}
+
+	progress, err := lessons.OpenProgress(path)
+	if err != nil {
+		log.Printf("Warning: lesson progress will not be saved: %v", err)
+		return lessons.NewProgress()
+	}
+	return progress
+}
+
 // runServerMode runs the SSH server for multiplayer games
-func runServerMode(port string, maxPlayers int) {
+func runServerMode(port string, maxPlayers, prefetch int, source quotes.Source, filter quotes.Filter, normalizer *quotes.Normalizer, history *quotes.History, leaderboard *quotes.Leaderboard, ratings *quotes.Ratings, adminKeys string, community float64) {
 	fmt.Printf("Starting TypeRacer Server on port %s (max %d players per room)...\n", port, maxPlayers)
 
-	server := NewSSHServer(port)
+	// Approved player submissions join the configured quotes
+	submissions := openSubmissions()
+	submissions.SetLibrary(source)
+	if community > 0 {
+		source = quotes.NewWeightedSource().
+			Add(source, 1-min(community, 1)).
+			Add(submissions, min(community, 1))
+	}
+
+	manager := game.NewManagerWithSource(source)
+	manager.SetQuoteFilter(filter)
+	manager.SetNormalizer(normalizer)
+	manager.SetPrefetchSize(prefetch)
+	manager.SetHistory(history)
+	manager.SetLeaderboard(leaderboard)
+	manager.SetRatings(ratings)
+	manager.SetSubmissions(submissions)
+	manager.PrefetchQuotes()
+	defer manager.Close()
+	server := NewSSHServer(port, manager)
+	if adminKeys != "" {
+		if err := server.LoadAdminKeys(adminKeys); err != nil {
+			log.Fatalf("Error loading admin keys: %v", err)
+		}
+	}
 
 	// Check for host key
 	if err := generateHostKey(); err != nil {
@@ -66,20 +565,124 @@ func runServerMode(port string, maxPlayers int) {
 	}
 }
 
+// defaultQuoteServerPort is the HTTP port used by quotes mode unless -port
+// is given
+const defaultQuoteServerPort = "8080"
+
+// flagSet reports whether a flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// runQuoteServerMode serves the configured quotes over HTTP in the format of
+// the quotable API, so other servers can use it with -quotes api -api-url
+func runQuoteServerMode(port string, source quotes.Source) {
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           quotes.NewServer(source),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Serving quotes on http://localhost:%s/random ...", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Error running quote server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down quote server...")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatalf("Error shutting down quote server: %v", err)
+	}
+}
+
 // showHelp displays help information
 func showHelp() {
 	fmt.Println("TypeRacer TUI - Terminal-based typing race game")
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  typeracer-tui [flags]")
+	fmt.Println("  typeracer-tui library <command>   Manage the local quote library")
 	fmt.Println()
 	fmt.Println("Flags:")
 	fmt.Println("  -mode string")
-	fmt.Println("        Mode to run: 'practice' or 'server' (default: practice)")
+	fmt.Println("        Mode to run: 'practice', 'lessons', 'server' or 'quotes' (default: practice)")
 	fmt.Println("  -port string")
-	fmt.Println("        SSH server port for server mode (default: 2222)")
+	fmt.Println("        SSH server port for server mode (default: 2222), or HTTP port")
+	fmt.Println("        for quotes mode (default: 8080)")
 	fmt.Println("  -players int")
 	fmt.Println("        Maximum players per room for server mode (default: 4)")
+	fmt.Println("  -prefetch int")
+	fmt.Println("        Quotes kept ready per difficulty for server mode (default: 5)")
+	fmt.Println("  -length string")
+	fmt.Println("        Quote length: 'short', 'medium' or 'long' (default: any)")
+	fmt.Println("  -difficulty string")
+	fmt.Println("        Quote difficulty: 'easy', 'medium' or 'hard' (default: any)")
+	fmt.Println("  -tags string")
+	fmt.Println("        Comma-separated quote tags, e.g. 'science,history'")
+	fmt.Println("  -language string")
+	fmt.Println("        Language to race in: 'en', 'de', 'es', 'fr' or 'ru' (default: en)")
+	fmt.Println("        In server mode players can pick another when connecting")
+	fmt.Println("  -quotes string")
+	fmt.Println("        Quote sources: 'library', 'embedded', 'api', 'dir:PATH' or 'code:PATH'")
+	fmt.Println("        (default: library, your local library or else the embedded quotes)")
+	fmt.Println("        Combine with weights, e.g. 'library=3,dir:./quotes=1'")
+	fmt.Println("        'code:PATH' serves snippets of source files; use -tags to pick languages")
+	fmt.Println("        'words:SIZE' generates random common words from the top 200, 1k, 5k or 10k;")
+	fmt.Println("        add '+punctuation' and/or '+numbers', e.g. 'words:1k+punctuation'")
+	fmt.Println("        'symbols[:PERCENT]' drills digits, brackets, operators and shifted")
+	fmt.Println("        symbols mixed with words, e.g. 'symbols:80' (default: 50)")
+	fmt.Println("  -api-url string")
+	fmt.Println("        Base URL of the quotable-compatible API used by -quotes api")
+	fmt.Println("        (default: https://api.quotable.io)")
+	fmt.Println("  -api-timeout duration")
+	fmt.Println("        Timeout for each request to the quote API (default: 10s)")
+	fmt.Println("  -lowercase")
+	fmt.Println("        Convert quotes to lower case")
+	fmt.Println("  -no-punctuation")
+	fmt.Println("        Strip punctuation from quotes")
+	fmt.Println("  -ascii")
+	fmt.Println("        Replace accented letters with plain ASCII")
+	fmt.Println("  -text string")
+	fmt.Println("        Practice on your own text file, or '-' for standard input")
+	fmt.Println("        Long documents are split into passages sized by -length and")
+	fmt.Println("        your position is remembered between runs")
+	fmt.Println("  -book string")
+	fmt.Println("        Type through a plain-text or EPUB book chapter by chapter,")
+	fmt.Println("        resuming from your bookmark on every run")
+	fmt.Println("  -from-start")
+	fmt.Println("        Start -text or -book from the first passage")
+	fmt.Println("  -bookmarks")
+	fmt.Println("        Show your progress through saved texts and books")
+	fmt.Println("  -reset-history")
+	fmt.Println("        Forget which quotes have been typed so they can come up again")
+	fmt.Println("  -leaderboard")
+	fmt.Println("        Show the top times and average WPM for the most typed quotes")
+	fmt.Println("  -admin-keys string")
+	fmt.Println("        authorized_keys file of players who may approve, edit or reject")
+	fmt.Println("        submitted quotes (server mode only)")
+	fmt.Println("  -community float")
+	fmt.Println("        Share of races drawn from approved player submissions (default: 0.2)")
+	fmt.Println("  -layout string")
+	fmt.Println("        Keyboard layout to emulate on a QWERTY keyboard: 'qwerty', 'dvorak',")
+	fmt.Println("        'colemak' or 'workman' (default: qwerty)")
+	fmt.Println("  -metric string")
+	fmt.Println("        Speed shown as the headline: 'net' WPM, which takes off uncorrected")
+	fmt.Println("        errors, 'gross' WPM or raw 'cpm' including deleted keys (default: net)")
 	fmt.Println("  -help")
 	fmt.Println("        Show this help message")
 	fmt.Println()
@@ -87,26 +690,67 @@ func showHelp() {
 	fmt.Println("  # Run practice mode")
 	fmt.Println("  typeracer-tui")
 	fmt.Println("  typeracer-tui -mode practice")
+	fmt.Println("  typeracer-tui -length short -difficulty easy")
+	fmt.Println("  typeracer-tui -quotes code:~/src/myproject -tags go")
+	fmt.Println("  typeracer-tui -quotes words:200+punctuation -length short")
+	fmt.Println("  typeracer-tui -quotes symbols:70")
+	fmt.Println("  typeracer-tui -language fr")
+	fmt.Println("  typeracer-tui -metric cpm")
+	fmt.Println("  typeracer-tui -text docs/spec.md")
+	fmt.Println("  cat notes.txt | typeracer-tui -text -")
+	fmt.Println("  typeracer-tui -book moby-dick.epub")
+	fmt.Println()
+	fmt.Println("  # Learn to touch-type, one row of keys at a time")
+	fmt.Println("  typeracer-tui -mode lessons")
+	fmt.Println("  typeracer-tui -mode lessons -layout colemak")
 	fmt.Println()
 	fmt.Println("  # Run server mode")
 	fmt.Println("  typeracer-tui -mode server")
 	fmt.Println("  typeracer-tui -mode server -port 2222 -players 4")
+	fmt.Println("  typeracer-tui -mode server -admin-keys ~/.ssh/authorized_keys")
+	fmt.Println()
+	fmt.Println("  # Serve quotes to other servers on an isolated network")
+	fmt.Println("  typeracer-tui -mode quotes -quotes dir:/srv/quotes")
+	fmt.Println("  typeracer-tui -mode server -quotes api -api-url http://quotes.internal:8080")
+	fmt.Println()
+	fmt.Println("  # Manage the local quote library")
+	fmt.Println("  typeracer-tui library search einstein")
+	fmt.Println("  typeracer-tui library import team-quotes.csv")
+	fmt.Println("  typeracer-tui library help")
 	fmt.Println()
 	fmt.Println("  # Connect to server")
 	fmt.Println("  ssh localhost -p 2222")
+	fmt.Println("  ssh localhost -p 2222 de   # race in German")
 	fmt.Println()
 	fmt.Println("Practice Mode:")
 	fmt.Println("  - Single-player typing practice")
 	fmt.Println("  - Real-time WPM and accuracy tracking")
+	fmt.Println("  - Net and gross WPM, raw CPM, corrected and uncorrected errors and consistency")
 	fmt.Println("  - Visual feedback for correct/incorrect typing")
 	fmt.Println("  - No network connection required")
 	fmt.Println()
+	fmt.Println("Lessons Mode:")
+	fmt.Println("  - Touch-typing course from the home row to numbers and punctuation")
+	fmt.Println("  - Each lesson only uses keys learned so far")
+	fmt.Println("  - Reach the speed and accuracy targets to unlock the next lesson")
+	fmt.Println("  - Lessons follow the -layout keyboard, with hints for every key")
+	fmt.Println()
 	fmt.Println("Server Mode:")
 	fmt.Println("  - Multiplayer typing races over SSH")
 	fmt.Println("  - Lobby system for player matchmaking")
 	fmt.Println("  - Real-time opponent progress tracking")
 	fmt.Println("  - Configurable room sizes (2-4 players)")
 	fmt.Println("  - 3-2-1-GO countdown before races")
+	fmt.Println("  - Players submit quotes from the lobby ('s'); admins review them ('m')")
+	fmt.Println("  - Lobbies grouped by language; players change language in the lobby ('g')")
+	fmt.Println("  - Each player picks a keyboard layout to emulate in the lobby ('l')")
+	fmt.Println("  - Each player picks the speed metric they see in the lobby ('w')")
+	fmt.Println("  - Players can reset their own quote history from the lobby ('h')")
+	fmt.Println("  - Races are timed and scored by the server from GO")
+	fmt.Println()
+	fmt.Println("Quotes Mode:")
+	fmt.Println("  - Serves the configured quotes over HTTP at /random")
+	fmt.Println("  - Same JSON as api.quotable.io, with minLength, maxLength and tags filters")
 	fmt.Println()
 	fmt.Println("Controls:")
 	fmt.Println("  - Type the displayed text as fast and accurately as possible")
@@ -114,4 +758,5 @@ func showHelp() {
 	fmt.Println("  - Ctrl+C or Esc to quit")
 	fmt.Println("  - 'r' to restart (practice mode)")
 	fmt.Println("  - 'q' to quit (results screen)")
+	fmt.Println("  - '+', '-' or 'f' to like, dislike or flag a passage (results screen)")
 }
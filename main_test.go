@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"typeracer-tui/quotes"
+)
+
+func TestParseQuoteSource(t *testing.T) {
+	// A directory whose name holds an '=' must not be read as a weight
+	dir := filepath.Join(t.TempDir(), "a=b")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	quote := `[{"content": "A quote from a directory.", "author": "Tester"}]`
+	if err := os.WriteFile(filepath.Join(dir, "quotes.json"), []byte(quote), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		spec         string
+		wantWeighted bool
+		wantErr      bool
+	}{
+		{name: "a path containing '='", spec: "dir:" + dir},
+		{name: "a weighted path containing '='", spec: "dir:" + dir + "=2,embedded=1", wantWeighted: true},
+		{name: "a fractional weight", spec: "embedded=0.5"},
+		{name: "a zero weight", spec: "embedded=0", wantErr: true},
+		{name: "a negative weight", spec: "embedded=-1", wantErr: true},
+		{name: "a weight that is not a number", spec: "embedded=NaN", wantErr: true},
+		{name: "a name that is not a source", spec: "embedded=heavy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := parseQuoteSource(tt.spec, quotes.FetcherOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQuoteSource(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, weighted := source.(*quotes.WeightedSource); weighted != tt.wantWeighted {
+				t.Errorf("parseQuoteSource(%q) = %T, want weighted %v", tt.spec, source, tt.wantWeighted)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package quotes
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// chapterHeading matches plain-text lines such as "CHAPTER IV" or
+// "Part 2: The Return" that may start a new chapter; isChapterHeading checks
+// what follows the keyword
+var chapterHeading = regexp.MustCompile(`(?i)^(chapter|book|part|act|letter)\s+([0-9]+|[a-z]+(?:-[a-z]+)?)\b[.:]?.{0,60}$`)
+
+// romanNumeral matches a well-formed roman numeral below 400, which covers
+// chapter numbers without taking words such as "mix" or "dim" for numbers
+var romanNumeral = regexp.MustCompile(`(?i)^c{0,3}(xc|xl|l?x{0,3})(ix|iv|v?i{0,3})$`)
+
+// numberWords are the spelled-out numbers chapters are commonly numbered
+// with, e.g. "Chapter Twenty-One" or "Part Second"
+var numberWords = map[string]bool{
+	"one": true, "two": true, "three": true, "four": true, "five": true,
+	"six": true, "seven": true, "eight": true, "nine": true, "ten": true,
+	"eleven": true, "twelve": true, "thirteen": true, "fourteen": true,
+	"fifteen": true, "sixteen": true, "seventeen": true, "eighteen": true,
+	"nineteen": true, "twenty": true, "thirty": true, "forty": true,
+	"fifty": true, "sixty": true, "seventy": true, "eighty": true,
+	"ninety": true, "hundred": true,
+	"first": true, "second": true, "third": true, "fourth": true,
+	"fifth": true, "sixth": true, "seventh": true, "eighth": true,
+	"ninth": true, "tenth": true, "eleventh": true, "twelfth": true,
+}
+
+// isChapterHeading reports whether a line starts a new chapter: a keyword
+// followed by a number, a roman numeral or a spelled-out number, or a line
+// in capitals such as "BOOK THE FIRST". Short paragraphs like "Act now."
+// are not headings.
+func isChapterHeading(line string) bool {
+	match := chapterHeading.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+
+	number := strings.ToLower(match[2])
+	if strings.Trim(number, "0123456789") == "" || romanNumeral.MatchString(number) {
+		return true
+	}
+	spelled := true
+	for _, part := range strings.Split(number, "-") {
+		spelled = spelled && numberWords[part]
+	}
+	return spelled || line == strings.ToUpper(line)
+}
+
+// LoadBook reads a plain-text or EPUB book and serves it chapter by chapter,
+// saving the chapter and passage in bookmarks
+func LoadBook(bookPath string, maxLength int, bookmarks *BookmarkStore) (*DocumentSource, error) {
+	data, err := os.ReadFile(bookPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read book: %w", err)
+	}
+
+	var document *Document
+	if strings.EqualFold(filepath.Ext(bookPath), ".epub") {
+		document, err = parseEPUB(bookPath, maxLength)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		document = parseTextBook(string(data), maxLength)
+	}
+	if document.Title == "" {
+		document.Title = strings.TrimSuffix(filepath.Base(bookPath), filepath.Ext(bookPath))
+	}
+
+	return NewDocumentSource(document, "book:"+absPath(bookPath), contentHash(string(data)), bookmarks)
+}
+
+// parseTextBook splits a plain-text book into chapters at heading lines
+func parseTextBook(text string, maxLength int) *Document {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	document := &Document{}
+	title, body := "", []string{}
+	flush := func() {
+		if passages := SplitPassages(strings.Join(body, "\n"), maxLength); len(passages) > 0 {
+			document.Chapters = append(document.Chapters, Chapter{Title: title, Passages: passages})
+		}
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		standalone := (i == 0 || strings.TrimSpace(lines[i-1]) == "") &&
+			(i+1 == len(lines) || strings.TrimSpace(lines[i+1]) == "")
+		if standalone && isChapterHeading(trimmed) {
+			flush()
+			title, body = trimmed, nil
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	return document
+}
+
+// EPUB package structures, reduced to the parts needed to read the text in
+// reading order
+type epubContainer struct {
+	Rootfiles []struct {
+		Path string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Title    string `xml:"metadata>title"`
+	Creator  string `xml:"metadata>creator"`
+	Manifest []struct {
+		ID   string `xml:"id,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// parseEPUB reads the chapters of an EPUB in spine order
+func parseEPUB(bookPath string, maxLength int) (*Document, error) {
+	archive, err := zip.OpenReader(bookPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open EPUB: %w", err)
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var container epubContainer
+	if err := decodeZipXML(files, "META-INF/container.xml", &container); err != nil {
+		return nil, err
+	}
+	if len(container.Rootfiles) == 0 {
+		return nil, fmt.Errorf("EPUB has no package document")
+	}
+
+	packagePath := container.Rootfiles[0].Path
+	var pkg epubPackage
+	if err := decodeZipXML(files, packagePath, &pkg); err != nil {
+		return nil, err
+	}
+
+	hrefs := make(map[string]string, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = path.Join(path.Dir(packagePath), item.Href)
+	}
+
+	document := &Document{
+		Title:  strings.TrimSpace(pkg.Title),
+		Author: strings.TrimSpace(pkg.Creator),
+	}
+	for _, item := range pkg.Spine {
+		file, ok := files[hrefs[item.IDRef]]
+		if !ok {
+			continue
+		}
+		title, text, err := readXHTML(file)
+		if err != nil {
+			return nil, err
+		}
+		passages := SplitPassages(text, maxLength)
+		if len(passages) == 0 {
+			continue
+		}
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", len(document.Chapters)+1)
+		}
+		document.Chapters = append(document.Chapters, Chapter{Title: title, Passages: passages})
+	}
+	return document, nil
+}
+
+// decodeZipXML decodes an XML file inside an archive
+func decodeZipXML(files map[string]*zip.File, name string, v interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("EPUB is missing %s", name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(reader).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// blockElements start a new paragraph in extracted XHTML text. A line break
+// only separates words, since poetry and addresses use it inside paragraphs.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "li": true, "blockquote": true, "section": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "tr": true,
+}
+
+// readXHTML extracts the first heading and the paragraph text of a chapter
+func readXHTML(file *zip.File) (string, string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	decoder := xml.NewDecoder(reader)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var text, heading strings.Builder
+	skip, inHeading, headingDone := 0, false, false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse %s: %w", file.Name, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case name == "script" || name == "style" || name == "head":
+				skip++
+			case name == "br":
+				text.WriteString(" ")
+			case blockElements[name]:
+				text.WriteString("\n\n")
+			}
+			if !headingDone && (name == "h1" || name == "h2" || name == "h3") {
+				inHeading = true
+			}
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case name == "script" || name == "style" || name == "head":
+				skip--
+			case blockElements[name]:
+				text.WriteString("\n\n")
+			}
+			if inHeading && (name == "h1" || name == "h2" || name == "h3") {
+				inHeading, headingDone = false, true
+			}
+		case xml.CharData:
+			if skip > 0 {
+				continue
+			}
+			if inHeading {
+				heading.Write(t)
+				continue
+			}
+			text.Write(t)
+		}
+	}
+
+	return strings.Join(strings.Fields(heading.String()), " "), text.String(), nil
+}
@@ -0,0 +1,101 @@
+package quotes
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIsChapterHeading(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{line: "CHAPTER IV", want: true},
+		{line: "Chapter 12", want: true},
+		{line: "Part 2: The Return", want: true},
+		{line: "Book xiv.", want: true},
+		{line: "Act I", want: true},
+		{line: "Chapter Twenty-One", want: true},
+		{line: "BOOK THE FIRST", want: true},
+		{line: "LETTER FROM HOME", want: true},
+		{line: "Act now.", want: false},
+		{line: "Chapter XL", want: true},
+		{line: "Chapter CXII", want: true},
+		{line: "Part mild", want: false},
+		{line: "Part mix", want: false},
+		{line: "Book dim", want: false},
+		{line: "Chapter lid", want: false},
+		{line: "Book the first", want: false},
+		{line: "Letter from home", want: false},
+		{line: "The chapter ends here.", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := isChapterHeading(tt.line); got != tt.want {
+				t.Errorf("isChapterHeading(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTextBook(t *testing.T) {
+	text := "CHAPTER I\n\nIt began on a quiet morning.\n\nAct now.\n\nNobody moved.\n\nCHAPTER II\n\nThen the rain came."
+	document := parseTextBook(text, 0)
+
+	if len(document.Chapters) != 2 {
+		t.Fatalf("got %d chapters, want 2", len(document.Chapters))
+	}
+	for i, title := range []string{"CHAPTER I", "CHAPTER II"} {
+		if document.Chapters[i].Title != title {
+			t.Errorf("chapter %d title = %q, want %q", i, document.Chapters[i].Title, title)
+		}
+	}
+}
+
+func TestParseEPUBLineBreaks(t *testing.T) {
+	files := map[string]string{
+		"META-INF/container.xml": `<container><rootfiles><rootfile full-path="OEBPS/content.opf"/></rootfiles></container>`,
+		"OEBPS/content.opf": `<package><metadata><title>Poems</title><creator>Anon</creator></metadata>` +
+			`<manifest><item id="c1" href="one.xhtml"/></manifest><spine><itemref idref="c1"/></spine></package>`,
+		"OEBPS/one.xhtml": `<html><body><h1>One</h1>` +
+			`<p>Roses are red,<br/>violets are blue.</p><p>A second paragraph follows.</p></body></html>`,
+	}
+
+	path := filepath.Join(t.TempDir(), "poems.epub")
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(out)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := parseEPUB(path, 300)
+	if err != nil {
+		t.Fatalf("parseEPUB() error: %v", err)
+	}
+	if len(document.Chapters) != 1 {
+		t.Fatalf("got %d chapters, want 1", len(document.Chapters))
+	}
+	want := []string{"Roses are red, violets are blue.", "A second paragraph follows."}
+	if got := document.Chapters[0].Passages; !slices.Equal(got, want) {
+		t.Errorf("passages = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package quotes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Bookmark records how far a player has typed through a document. Position
+// is the passage within the chapter; Done and Total count passages across
+// the whole document.
+type Bookmark struct {
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Chapter   int       `json:"chapter,omitempty"`
+	Chapters  int       `json:"chapters,omitempty"`
+	Position  int       `json:"position"`
+	Done      int       `json:"done"`
+	Total     int       `json:"total"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Percent returns how much of the document has been typed
+func (b Bookmark) Percent() float64 {
+	if b.Total == 0 {
+		return 0
+	}
+	return float64(b.Done) / float64(b.Total) * 100
+}
+
+// BookmarkStore keeps bookmarks in a JSON file so practice can resume where
+// it left off
+type BookmarkStore struct {
+	path      string
+	bookmarks map[string]Bookmark
+	mu        sync.Mutex
+}
+
+// DefaultBookmarkPath returns the bookmark file in the user's config directory
+func DefaultBookmarkPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "typeracer-tui", "bookmarks.json"), nil
+}
+
+// OpenBookmarkStore loads bookmarks from path; a missing file is an empty store
+func OpenBookmarkStore(path string) (*BookmarkStore, error) {
+	store := &BookmarkStore{path: path, bookmarks: make(map[string]Bookmark)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.bookmarks); err != nil {
+		return nil, fmt.Errorf("failed to parse bookmarks: %w", err)
+	}
+	return store, nil
+}
+
+// Get returns the bookmark for a document key
+func (s *BookmarkStore) Get(key string) (Bookmark, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookmark, exists := s.bookmarks[key]
+	return bookmark, exists
+}
+
+// All returns every bookmark by document key
+func (s *BookmarkStore) All() map[string]Bookmark {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookmarks := make(map[string]Bookmark, len(s.bookmarks))
+	for key, bookmark := range s.bookmarks {
+		bookmarks[key] = bookmark
+	}
+	return bookmarks
+}
+
+// Set stores a bookmark and writes the file
+func (s *BookmarkStore) Set(key string, bookmark Bookmark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bookmark.UpdatedAt = time.Now()
+	s.bookmarks[key] = bookmark
+	return s.save()
+}
+
+// save writes the bookmarks file
+func (s *BookmarkStore) save() error {
+	if err := WriteJSONFile(s.path, s.bookmarks); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	return nil
+}
+
+// contentHash identifies a document by its contents
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:8])
+}
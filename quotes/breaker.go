@@ -0,0 +1,179 @@
+package quotes
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned while a failing upstream is being left alone
+var ErrCircuitOpen = errors.New("quote API is down; waiting before trying again")
+
+// BreakerState describes whether requests are let through to an upstream
+type BreakerState string
+
+// Circuit breaker states
+const (
+	// BreakerClosed lets every request through; the upstream is healthy
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects requests until the cooldown has passed
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single trial request through after the cooldown
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// SourceHealth reports how a remote quote source has been responding
+type SourceHealth struct {
+	Name                string       `json:"name"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastError           string       `json:"last_error,omitempty"`
+	LastSuccess         time.Time    `json:"last_success,omitempty"`
+	LastFailure         time.Time    `json:"last_failure,omitempty"`
+	// RetryAt is when an open breaker will let a trial request through
+	RetryAt time.Time `json:"retry_at,omitempty"`
+}
+
+// Healthy reports whether requests are currently let through
+func (h SourceHealth) Healthy() bool {
+	return h.State == BreakerClosed
+}
+
+// HealthReporter is implemented by sources that can tell whether their
+// upstream is reachable
+type HealthReporter interface {
+	Health() SourceHealth
+}
+
+// HealthOf returns the health of every remote source within source
+func HealthOf(source Source) []SourceHealth {
+	switch source := source.(type) {
+	case HealthReporter:
+		return []SourceHealth{source.Health()}
+	case *WeightedSource:
+		var health []SourceHealth
+		for _, entry := range source.entries {
+			health = append(health, HealthOf(entry.source)...)
+		}
+		return health
+	}
+	return nil
+}
+
+// circuitBreaker stops calls to an upstream after repeated failures and
+// lets a single trial call through once a cooldown has passed
+type circuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	onChange  func(SourceHealth)
+	health    SourceHealth
+	probing   bool
+	mu        sync.Mutex
+}
+
+// newCircuitBreaker creates a closed breaker that opens after threshold
+// consecutive failures
+func newCircuitBreaker(name string, threshold int, cooldown time.Duration, onChange func(SourceHealth)) *circuitBreaker {
+	return &circuitBreaker{
+		name:      name,
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		onChange:  onChange,
+		health:    SourceHealth{Name: name, State: BreakerClosed},
+	}
+}
+
+// allow reports whether a call may go ahead, returning ErrCircuitOpen if not
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	changed := false
+	switch b.health.State {
+	case BreakerOpen:
+		if time.Now().Before(b.health.RetryAt) {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		changed = b.setState(BreakerHalfOpen)
+	case BreakerHalfOpen:
+		// Only one trial call at a time
+		if b.probing {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+	}
+	if b.health.State == BreakerHalfOpen {
+		b.probing = true
+	}
+	b.unlock(changed)
+	return nil
+}
+
+// success records a call that worked, closing the breaker
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	b.health.LastSuccess = time.Now()
+	b.close()
+}
+
+// reachable records a call the upstream answered without it being of use,
+// such as a client error; the upstream is up, so the breaker closes
+func (b *circuitBreaker) reachable() {
+	b.mu.Lock()
+	b.close()
+}
+
+// close resets the failure count and closes the breaker; the caller holds
+// the lock, which is released
+func (b *circuitBreaker) close() {
+	b.probing = false
+	b.health.ConsecutiveFailures = 0
+	b.health.RetryAt = time.Time{}
+	b.unlock(b.setState(BreakerClosed))
+}
+
+// failure records a call that failed, opening the breaker after too many in
+// a row or when a trial call fails
+func (b *circuitBreaker) failure(err error) {
+	b.mu.Lock()
+	b.probing = false
+	b.health.ConsecutiveFailures++
+	b.health.LastError = err.Error()
+	b.health.LastFailure = time.Now()
+
+	changed := false
+	if b.health.State == BreakerHalfOpen || b.health.ConsecutiveFailures >= b.threshold {
+		b.health.RetryAt = b.health.LastFailure.Add(b.cooldown)
+		changed = b.setState(BreakerOpen)
+	}
+	b.unlock(changed)
+}
+
+// snapshot returns the current health
+func (b *circuitBreaker) snapshot() SourceHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.health
+}
+
+// setState moves to a new state, reporting whether it changed; the caller
+// holds the lock
+func (b *circuitBreaker) setState(state BreakerState) bool {
+	if b.health.State == state {
+		return false
+	}
+	b.health.State = state
+	return true
+}
+
+// unlock releases the lock and then reports a state change, so the
+// callback may safely ask for the health itself
+func (b *circuitBreaker) unlock(changed bool) {
+	health := b.health
+	b.mu.Unlock()
+
+	if changed && b.onChange != nil {
+		b.onChange(health)
+	}
+}
@@ -0,0 +1,225 @@
+package quotes
+
+import (
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// codeLanguages maps source file extensions to the language tag given to
+// snippets taken from them
+var codeLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".jsx":   "javascript",
+	".rs":    "rust",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".java":  "java",
+	".kt":    "kotlin",
+	".rb":    "ruby",
+	".php":   "php",
+	".cs":    "csharp",
+	".swift": "swift",
+	".scala": "scala",
+	".lua":   "lua",
+	".sh":    "shell",
+	".sql":   "sql",
+	".hs":    "haskell",
+	".ex":    "elixir",
+	".exs":   "elixir",
+	".zig":   "zig",
+}
+
+// skippedCodeDirs are directories that hold generated or third-party code
+var skippedCodeDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// Snippet limits
+const (
+	minSnippetLength  = 20
+	maxSnippetLine    = 100
+	maxSnippetLength  = 400
+	maxSnippetFileLen = 1 << 20
+)
+
+// CodeSource serves snippets of source code from files under a directory,
+// keeping their newlines and relative indentation
+type CodeSource struct {
+	root  string
+	files []string
+}
+
+// NewCodeSource scans root for source files in known languages
+func NewCodeSource(root string) (*CodeSource, error) {
+	source := &CodeSource{root: root}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != root && (strings.HasPrefix(name, ".") || skippedCodeDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := codeLanguages[strings.ToLower(filepath.Ext(path))]; ok {
+			source.files = append(source.files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan code directory: %w", err)
+	}
+
+	if len(source.files) == 0 {
+		return nil, fmt.Errorf("no source files found in %s", root)
+	}
+	return source, nil
+}
+
+// Random returns a snippet from a random file matching the filter. Tags
+// match the snippet's language, e.g. "go" or "python".
+func (s *CodeSource) Random(filter Filter) (*Quote, error) {
+	const attempts = 20
+	for i := 0; i < attempts; i++ {
+		path := s.files[rand.Intn(len(s.files))]
+		quote, err := s.snippet(path, filter.Length)
+		if err != nil {
+			continue
+		}
+		if filter.Matches(quote) {
+			return quote, nil
+		}
+	}
+	return nil, fmt.Errorf("no code snippet matches the requested filter")
+}
+
+// snippet cuts a run of lines out of a file, sized for the length bucket
+func (s *CodeSource) snippet(path string, length LengthBucket) (*Quote, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxSnippetFileLen {
+		return nil, fmt.Errorf("%s is too large", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	// Start on a non-blank line so the snippet never opens with whitespace
+	var starts []int
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			starts = append(starts, i)
+		}
+	}
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+
+	minLength, maxLength := snippetBounds(length)
+	start := starts[rand.Intn(len(starts))]
+
+	var picked []string
+	total := 0
+	for _, line := range lines[start:] {
+		line = strings.TrimRight(line, " \t")
+		if len(line) > maxSnippetLine || !isTypeableCode(line) {
+			break
+		}
+		if total+len(line)+1 > maxLength {
+			break
+		}
+		picked = append(picked, line)
+		total += len(line) + 1
+	}
+
+	content := strings.TrimRight(dedent(picked), "\n")
+	if len(content) < minLength {
+		return nil, fmt.Errorf("snippet from %s is too short", path)
+	}
+
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		rel = path
+	}
+	quote := &Quote{
+		Content: content,
+		Author:  filepath.ToSlash(rel),
+		Tags:    []string{codeLanguages[strings.ToLower(filepath.Ext(path))]},
+		Code:    true,
+	}
+	Identify(quote, "code")
+	return quote, nil
+}
+
+// snippetBounds returns the size range of snippets for a length bucket
+func snippetBounds(length LengthBucket) (int, int) {
+	switch length {
+	case LengthShort:
+		return minSnippetLength, shortMaxLength
+	case LengthMedium:
+		return shortMaxLength + 1, mediumMaxLength
+	case LengthLong:
+		return mediumMaxLength + 1, maxSnippetLength
+	default:
+		return minSnippetLength * 2, mediumMaxLength
+	}
+}
+
+// isTypeableCode reports whether a line holds only printable ASCII and tabs
+func isTypeableCode(line string) bool {
+	for _, r := range line {
+		if r > unicode.MaxASCII || (r != '\t' && !unicode.IsPrint(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// dedent removes the indentation shared by every non-blank line
+func dedent(lines []string) string {
+	common := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			common, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.TrimPrefix(line, common)
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,186 @@
+package quotes
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Difficulty score boundaries on the 0-100 scale
+const (
+	easyMaxScore   = 25
+	mediumMaxScore = 35
+)
+
+// Weights of each difficulty component; they add up to 100
+const (
+	lengthWeight   = 15
+	rarityWeight   = 30
+	symbolWeight   = 25
+	capitalWeight  = 10
+	bigramWeight   = 20
+	longQuoteRunes = 400
+)
+
+// commonWordCount is how many of the most frequent English words are not
+// considered rare
+const commonWordCount = 1000
+
+// longWordLength is the length beyond which a word counts as rare in
+// languages without a word list
+const longWordLength = 8
+
+// DifficultyScore breaks down how hard a quote is to type. Each component
+// is already weighted, so Total is simply their sum on a 0-100 scale.
+type DifficultyScore struct {
+	Length   float64 `json:"length"`
+	Rarity   float64 `json:"rarity"`
+	Symbols  float64 `json:"symbols"`
+	Capitals float64 `json:"capitals"`
+	Bigrams  float64 `json:"bigrams"`
+	Total    float64 `json:"total"`
+}
+
+// Level converts the score into a coarse difficulty
+func (s DifficultyScore) Level() Difficulty {
+	switch {
+	case s.Total < easyMaxScore:
+		return DifficultyEasy
+	case s.Total < mediumMaxScore:
+		return DifficultyMedium
+	default:
+		return DifficultyHard
+	}
+}
+
+// Label returns a display name for the difficulty
+func (d Difficulty) Label() string {
+	if d == DifficultyAny {
+		return "any"
+	}
+	return string(d)
+}
+
+// Next cycles through any, easy, medium and hard
+func (d Difficulty) Next() Difficulty {
+	switch d {
+	case DifficultyAny:
+		return DifficultyEasy
+	case DifficultyEasy:
+		return DifficultyMedium
+	case DifficultyMedium:
+		return DifficultyHard
+	default:
+		return DifficultyAny
+	}
+}
+
+var (
+	commonWords     map[string]bool
+	commonWordsOnce sync.Once
+)
+
+// isCommonWord reports whether a lower-case word is among the most
+// frequent English words
+func isCommonWord(word string) bool {
+	commonWordsOnce.Do(func() {
+		commonWords = make(map[string]bool)
+		for _, w := range EnglishWords(commonWordCount) {
+			commonWords[strings.ToLower(w)] = true
+		}
+	})
+	return commonWords[word]
+}
+
+// qwertyFingers assigns each letter to the finger that types it on a QWERTY
+// keyboard, numbered left pinky (0) to right pinky (7)
+var qwertyFingers = map[rune]int{
+	'q': 0, 'a': 0, 'z': 0,
+	'w': 1, 's': 1, 'x': 1,
+	'e': 2, 'd': 2, 'c': 2,
+	'r': 3, 'f': 3, 'v': 3, 't': 3, 'g': 3, 'b': 3,
+	'y': 4, 'h': 4, 'n': 4, 'u': 4, 'j': 4, 'm': 4,
+	'i': 5, 'k': 5,
+	'o': 6, 'l': 6,
+	'p': 7,
+}
+
+// ScoreDifficulty estimates how hard a quote is to type from its length,
+// the share of uncommon words, punctuation and digit density, capitals and
+// the share of letter pairs typed by the same finger
+func ScoreDifficulty(q *Quote) DifficultyScore {
+	runes := []rune(q.Content)
+	if len(runes) == 0 {
+		return DifficultyScore{}
+	}
+
+	var score DifficultyScore
+	score.Length = math.Min(float64(len(runes))/longQuoteRunes, 1) * lengthWeight
+
+	words := strings.FieldsFunc(strings.ToLower(q.Content), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) > 0 {
+		// The word list is English; in other languages long words stand in
+		// for rare ones
+		english := LanguageOf(q) == English
+		rare := 0
+		for _, word := range words {
+			word = strings.Trim(word, "'")
+			if english && !isCommonWord(word) || !english && GraphemeCount(word) > longWordLength {
+				rare++
+			}
+		}
+		score.Rarity = float64(rare) / float64(len(words)) * rarityWeight
+	}
+
+	letters, capitals, symbols, pairs, sameFinger := 0, 0, 0, 0, 0
+	prev := rune(0)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+			// A sentence-starting capital is free
+			if unicode.IsUpper(r) && i > 0 {
+				capitals++
+			}
+		case r == '.' && i == len(runes)-1:
+			// So is a final full stop
+		case unicode.IsDigit(r), unicode.IsPunct(r), unicode.IsSymbol(r):
+			symbols++
+		}
+
+		lower := unicode.ToLower(r)
+		finger, ok := qwertyFingers[lower]
+		if prevFinger, prevOK := qwertyFingers[prev]; ok && prevOK {
+			pairs++
+			if finger == prevFinger && lower != prev {
+				sameFinger++
+			}
+		}
+		prev = lower
+	}
+
+	// Density above roughly one symbol in eight characters is as bad as it
+	// gets
+	score.Symbols = math.Min(float64(symbols)/float64(len(runes))*8, 1) * symbolWeight
+	if letters > 0 {
+		score.Capitals = math.Min(float64(capitals)/float64(letters)*10, 1) * capitalWeight
+	}
+	if pairs > 0 {
+		score.Bigrams = math.Min(float64(sameFinger)/float64(pairs)*8, 1) * bigramWeight
+	}
+
+	score.Total = score.Length + score.Rarity + score.Symbols + score.Capitals + score.Bigrams
+	return score
+}
+
+// DifficultyOf returns the coarse difficulty of a quote, scoring it only if
+// it was not scored when loaded or normalized
+func DifficultyOf(q *Quote) Difficulty {
+	if q.Difficulty != DifficultyAny {
+		return q.Difficulty
+	}
+	return ScoreDifficulty(q).Level()
+}
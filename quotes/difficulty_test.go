@@ -0,0 +1,40 @@
+package quotes
+
+import "testing"
+
+func TestScoreDifficultySentenceIsFree(t *testing.T) {
+	tests := []struct {
+		content      string
+		wantCapitals bool
+		wantSymbols  bool
+	}{
+		{content: "the cat sat on the mat"},
+		{content: "The cat sat on the mat."},
+		{content: "The Cat sat on the mat.", wantCapitals: true},
+		{content: "The cat sat on the mat!", wantSymbols: true},
+		{content: "The cat, sat on the mat.", wantSymbols: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			score := ScoreDifficulty(&Quote{Content: tt.content})
+			if (score.Capitals > 0) != tt.wantCapitals {
+				t.Errorf("Capitals = %v, want capitals scored %v", score.Capitals, tt.wantCapitals)
+			}
+			if (score.Symbols > 0) != tt.wantSymbols {
+				t.Errorf("Symbols = %v, want symbols scored %v", score.Symbols, tt.wantSymbols)
+			}
+		})
+	}
+}
+
+func TestDifficultyOfIsScoredOnLoad(t *testing.T) {
+	library := NewLibrary([]Quote{{Content: "A short and simple line."}})
+	quote := library.All()[0]
+	if quote.Difficulty == DifficultyAny {
+		t.Fatal("library quote was not scored when loaded")
+	}
+	if got, want := DifficultyOf(&quote), ScoreDifficulty(&quote).Level(); got != want {
+		t.Errorf("DifficultyOf() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package quotes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadDir builds a library from every .txt and .json file in a directory.
+//
+// JSON files hold an array of quotes in the same shape as the embedded
+// library. Text files hold one quote per paragraph; a final line starting
+// with "--" or "—" names the author, otherwise the file name is used.
+func LoadDir(dir string) (*Library, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quote directory: %w", err)
+	}
+
+	var quotes []Quote
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json":
+			loaded, err := loadJSONFile(path)
+			if err != nil {
+				return nil, err
+			}
+			quotes = append(quotes, loaded...)
+		case ".txt":
+			loaded, err := loadTextFile(path)
+			if err != nil {
+				return nil, err
+			}
+			quotes = append(quotes, loaded...)
+		}
+	}
+
+	for i := range quotes {
+		if quotes[i].Source == "" {
+			quotes[i].Source = "dir"
+		}
+	}
+
+	library := NewLibrary(quotes)
+	if library.Len() == 0 {
+		return nil, fmt.Errorf("no quotes found in %s", dir)
+	}
+	return library, nil
+}
+
+// loadJSONFile reads an array of quotes from a JSON file
+func loadJSONFile(path string) ([]Quote, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var quotes []Quote
+	if err := json.Unmarshal(data, &quotes); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return quotes, nil
+}
+
+// loadTextFile reads blank-line separated quotes from a text file
+func loadTextFile(path string) ([]Quote, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	defaultAuthor := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	normalized := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var quotes []Quote
+	for _, paragraph := range strings.Split(normalized, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		if len(lines) == 0 || lines[0] == "" {
+			continue
+		}
+
+		author := defaultAuthor
+		last := strings.TrimSpace(lines[len(lines)-1])
+		for _, prefix := range []string{"--", "—"} {
+			if strings.HasPrefix(last, prefix) && len(lines) > 1 {
+				author = strings.TrimSpace(strings.TrimPrefix(last, prefix))
+				lines = lines[:len(lines)-1]
+				break
+			}
+		}
+
+		quotes = append(quotes, Quote{
+			Content: strings.Join(strings.Fields(strings.Join(lines, " ")), " "),
+			Author:  author,
+		})
+	}
+	return quotes, nil
+}
@@ -0,0 +1,227 @@
+package quotes
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Chapter is a titled run of passages within a document
+type Chapter struct {
+	Title    string
+	Passages []string
+}
+
+// Document is a long text split into chapters of passage-sized chunks
+type Document struct {
+	Title    string
+	Author   string
+	Chapters []Chapter
+}
+
+// Passages returns the number of passages in the whole document
+func (d *Document) Passages() int {
+	total := 0
+	for _, chapter := range d.Chapters {
+		total += len(chapter.Passages)
+	}
+	return total
+}
+
+// DocumentProgress reports how far through a document the reader is.
+// Indexes are zero-based.
+type DocumentProgress struct {
+	Title        string
+	Chapter      string
+	ChapterIndex int
+	Chapters     int
+	Passage      int
+	Passages     int
+	Done         int
+	Total        int
+}
+
+// Percent returns how much of the whole document has been typed
+func (p DocumentProgress) Percent() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(p.Done) / float64(p.Total) * 100
+}
+
+// DocumentSource serves a document's passages in order, remembering the
+// chapter and passage in a bookmark store between runs
+type DocumentSource struct {
+	document  *Document
+	key       string
+	hash      string
+	chapter   int
+	passage   int
+	bookmarks *BookmarkStore
+	mu        sync.Mutex
+}
+
+// NewDocumentSource serves document from the start. When bookmarks is
+// non-nil the position is restored from and saved under key; a bookmark
+// made for different contents, identified by hash, is ignored.
+func NewDocumentSource(document *Document, key, hash string, bookmarks *BookmarkStore) (*DocumentSource, error) {
+	// Drop chapters without text so every position holds a passage
+	chapters := document.Chapters[:0]
+	for _, chapter := range document.Chapters {
+		if len(chapter.Passages) > 0 {
+			chapters = append(chapters, chapter)
+		}
+	}
+	document.Chapters = chapters
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("%s contains no text", document.Title)
+	}
+
+	source := &DocumentSource{
+		document:  document,
+		key:       key,
+		hash:      hash,
+		bookmarks: bookmarks,
+	}
+	if bookmarks != nil {
+		if bookmark, ok := bookmarks.Get(key); ok && bookmark.Hash == hash &&
+			bookmark.Chapter < len(chapters) && bookmark.Position < len(chapters[bookmark.Chapter].Passages) {
+			source.chapter = bookmark.Chapter
+			source.passage = bookmark.Position
+		}
+	}
+	return source, nil
+}
+
+// NewTextSource splits plain text into passages served as a single chapter
+func NewTextSource(name, key, text string, maxLength int, bookmarks *BookmarkStore) (*DocumentSource, error) {
+	document := &Document{
+		Title:    name,
+		Chapters: []Chapter{{Passages: SplitPassages(text, maxLength)}},
+	}
+	return NewDocumentSource(document, key, contentHash(text), bookmarks)
+}
+
+// LoadTextFile reads a document from path, or from standard input when path
+// is "-", and serves it passage by passage
+func LoadTextFile(path string, maxLength int, bookmarks *BookmarkStore) (*DocumentSource, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read standard input: %w", err)
+		}
+		text := string(data)
+		return NewTextSource("standard input", "stdin:"+contentHash(text), text, maxLength, bookmarks)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read text: %w", err)
+	}
+	return NewTextSource(filepath.Base(path), "file:"+absPath(path), string(data), maxLength, bookmarks)
+}
+
+// Random returns the passage at the current position. The filter does not
+// apply; passages are already sized when the document is split.
+func (s *DocumentSource) Random(filter Filter) (*Quote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chapter := s.document.Chapters[s.chapter]
+	content := chapter.Passages[s.passage]
+
+	author := s.document.Title
+	if s.document.Author != "" {
+		author += " by " + s.document.Author
+	}
+	if chapter.Title != "" {
+		author += ", " + chapter.Title
+	}
+	author += fmt.Sprintf(", passage %d of %d", s.passage+1, len(chapter.Passages))
+
+	quote := &Quote{
+		Content: content,
+		Author:  author,
+	}
+	Identify(quote, s.key)
+	return quote, nil
+}
+
+// Advance moves to the next passage, on to the next chapter at the end of
+// one and back to the start at the end of the document
+func (s *DocumentSource) Advance() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.passage++
+	if s.passage >= len(s.document.Chapters[s.chapter].Passages) {
+		s.passage = 0
+		s.chapter = (s.chapter + 1) % len(s.document.Chapters)
+	}
+	return s.save()
+}
+
+// Reset moves back to the first passage
+func (s *DocumentSource) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chapter, s.passage = 0, 0
+	return s.save()
+}
+
+// Progress reports the current chapter and passage
+func (s *DocumentSource) Progress() DocumentProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.progress()
+}
+
+// progress builds the progress report; the caller holds the lock
+func (s *DocumentSource) progress() DocumentProgress {
+	done := s.passage
+	for _, chapter := range s.document.Chapters[:s.chapter] {
+		done += len(chapter.Passages)
+	}
+
+	chapter := s.document.Chapters[s.chapter]
+	return DocumentProgress{
+		Title:        s.document.Title,
+		Chapter:      chapter.Title,
+		ChapterIndex: s.chapter,
+		Chapters:     len(s.document.Chapters),
+		Passage:      s.passage,
+		Passages:     len(chapter.Passages),
+		Done:         done,
+		Total:        s.document.Passages(),
+	}
+}
+
+// save records the position in the bookmark store
+func (s *DocumentSource) save() error {
+	if s.bookmarks == nil {
+		return nil
+	}
+
+	progress := s.progress()
+	return s.bookmarks.Set(s.key, Bookmark{
+		Name:     s.document.Title,
+		Hash:     s.hash,
+		Chapter:  s.chapter,
+		Chapters: progress.Chapters,
+		Position: s.passage,
+		Done:     progress.Done,
+		Total:    progress.Total,
+	})
+}
+
+// absPath returns an absolute path, or path itself if it cannot be resolved
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
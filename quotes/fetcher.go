@@ -4,100 +4,273 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Quote represents a quote from the API
 type Quote struct {
-	Content string `json:"content"`
-	Author  string `json:"author"`
+	// ID identifies the passage across sources and runs; see Identify
+	ID      string   `json:"id,omitempty"`
+	Content string   `json:"content"`
+	Author  string   `json:"author"`
+	Tags    []string `json:"tags,omitempty"`
+	Length  int      `json:"length,omitempty"`
+	// Language is the language the quote is written in; empty means
+	// DefaultLanguage
+	Language Language `json:"language,omitempty"`
+	// Source names where the quote came from, e.g. "embedded" or "api"
+	Source string `json:"source,omitempty"`
+	// Category is the quote's length bucket
+	Category LengthBucket `json:"category,omitempty"`
+	// Difficulty caches DifficultyOf; it is not saved so a change to the
+	// scoring applies to stored quotes too
+	Difficulty Difficulty `json:"-"`
+	// Code marks source code snippets whose newlines and indentation are
+	// part of the text to type
+	Code bool `json:"code,omitempty"`
+}
+
+// DefaultAPIURL is the quotable-compatible API used when none is configured
+const DefaultAPIURL = "https://api.quotable.io"
+
+// FetcherOptions configures how a Fetcher talks to the quote API
+type FetcherOptions struct {
+	// BaseURL is the API root; /random is requested below it
+	BaseURL string
+	// Timeout bounds each HTTP request
+	Timeout time.Duration
+	// Retries is how many times a failed request is repeated
+	Retries int
+	// Backoff is the delay before the first retry, doubling up to MaxBackoff;
+	// each delay is jittered so clients do not retry in lockstep
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// FailureThreshold is how many failed requests in a row stop requests
+	// for Cooldown
+	FailureThreshold int
+	Cooldown         time.Duration
+	// OnHealthChange, if set, is called whenever the API goes down or
+	// comes back
+	OnHealthChange func(SourceHealth)
+}
+
+// DefaultFetcherOptions returns the options used by NewFetcher
+func DefaultFetcherOptions() FetcherOptions {
+	return FetcherOptions{
+		BaseURL:          DefaultAPIURL,
+		Timeout:          10 * time.Second,
+		Retries:          2,
+		Backoff:          250 * time.Millisecond,
+		MaxBackoff:       4 * time.Second,
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
 }
 
 // Fetcher handles quote retrieval
 type Fetcher struct {
 	client  *http.Client
 	baseURL string
+	options FetcherOptions
+	breaker *circuitBreaker
 }
 
 // NewFetcher creates a new quote fetcher
 func NewFetcher() *Fetcher {
+	return NewFetcherWithOptions(DefaultFetcherOptions())
+}
+
+// NewFetcherWithOptions creates a quote fetcher for the given API, falling
+// back to the defaults for an empty URL and non-positive timeout, failure
+// threshold or cooldown
+func NewFetcherWithOptions(options FetcherOptions) *Fetcher {
+	defaults := DefaultFetcherOptions()
+	if options.BaseURL == "" {
+		options.BaseURL = defaults.BaseURL
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaults.Timeout
+	}
+	if options.Retries < 0 {
+		options.Retries = 0
+	}
+	if options.MaxBackoff < options.Backoff {
+		options.MaxBackoff = options.Backoff
+	}
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = defaults.FailureThreshold
+	}
+	if options.Cooldown <= 0 {
+		options.Cooldown = defaults.Cooldown
+	}
+
+	baseURL := strings.TrimSuffix(options.BaseURL, "/")
 	return &Fetcher{
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: options.Timeout,
 		},
-		baseURL: "https://api.quotable.io",
+		baseURL: baseURL,
+		options: options,
+		breaker: newCircuitBreaker(baseURL, options.FailureThreshold, options.Cooldown, options.OnHealthChange),
 	}
 }
 
+// Health reports whether the API has been responding
+func (f *Fetcher) Health() SourceHealth {
+	return f.breaker.snapshot()
+}
+
 // FetchRandomQuote fetches a random quote from the API
 func (f *Fetcher) FetchRandomQuote() (*Quote, error) {
-	url := f.baseURL + "/random"
+	return f.fetch(f.baseURL + "/random")
+}
 
+// Random fetches a random quote matching the filter. Length, tags and
+// language are passed to the API; difficulty is checked locally, retrying a
+// few times. Quotes without a language are taken to be in English, as on
+// api.quotable.io.
+func (f *Fetcher) Random(filter Filter) (*Quote, error) {
+	query := url.Values{}
+	switch filter.Length {
+	case LengthShort:
+		query.Set("maxLength", strconv.Itoa(shortMaxLength))
+	case LengthMedium:
+		query.Set("minLength", strconv.Itoa(shortMaxLength+1))
+		query.Set("maxLength", strconv.Itoa(mediumMaxLength))
+	case LengthLong:
+		query.Set("minLength", strconv.Itoa(mediumMaxLength+1))
+	}
+	if len(filter.Tags) > 0 {
+		query.Set("tags", strings.Join(filter.Tags, ","))
+	}
+	if filter.Language != LanguageAny {
+		query.Set("language", string(filter.Language))
+	}
+
+	endpoint := f.baseURL + "/random"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	const attempts = 3
+	for i := 0; i < attempts; i++ {
+		quote, err := f.fetch(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Matches(quote) {
+			return quote, nil
+		}
+	}
+	return nil, fmt.Errorf("API returned no quote matching the requested filter")
+}
+
+// fetch retrieves and decodes a single quote from the given URL, retrying
+// transient failures with backoff. Only transient failures count towards
+// the circuit breaker; while the API is down requests fail straight away
+// with ErrCircuitOpen.
+func (f *Fetcher) fetch(url string) (*Quote, error) {
+	if err := f.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= f.options.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(f.backoff(attempt))
+		}
+
+		quote, transient, err := f.fetchOnce(url)
+		if err == nil {
+			f.breaker.success()
+			return quote, nil
+		}
+		if !transient {
+			// The API answered, so it is up even though the answer was no use
+			f.breaker.reachable()
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	f.breaker.failure(lastErr)
+	return nil, lastErr
+}
+
+// fetchOnce makes a single request, reporting whether a failure is
+// transient: a transport error, a server error or rate limiting, which may
+// clear up and so are retried and count as the API being down
+func (f *Fetcher) fetchOnce(url string) (*Quote, bool, error) {
 	resp, err := f.client.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch quote: %w", err)
+		return nil, true, fmt.Errorf("failed to fetch quote: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		transient := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		return nil, transient, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var quote Quote
 	if err := json.Unmarshal(body, &quote); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		return nil, false, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
 	// Validate quote content
 	if quote.Content == "" {
-		return nil, fmt.Errorf("received empty quote content")
+		return nil, false, fmt.Errorf("received empty quote content")
 	}
+	Identify(&quote, "api")
+
+	return &quote, false, nil
+}
 
-	return &quote, nil
+// backoff returns how long to wait before a retry: an exponentially growing
+// delay, capped, with the upper half jittered
+func (f *Fetcher) backoff(attempt int) time.Duration {
+	delay := f.options.Backoff << (attempt - 1)
+	if delay <= 0 || delay > f.options.MaxBackoff {
+		delay = f.options.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
 }
 
-// FetchRandomQuoteWithFallback fetches a quote with fallback to hardcoded quotes
+// FetchRandomQuoteWithFallback fetches a quote, falling back to the embedded
+// library when the API is unavailable
 func (f *Fetcher) FetchRandomQuoteWithFallback() *Quote {
 	quote, err := f.FetchRandomQuote()
 	if err != nil {
-		// Return a fallback quote if API fails
-		return &Quote{
-			Content: "The quick brown fox jumps over the lazy dog.",
-			Author:  "Fallback",
-		}
+		return RandomQuote(Filter{})
 	}
 	return quote
 }
 
-// GetFallbackQuotes returns a list of hardcoded quotes for offline use
-func GetFallbackQuotes() []Quote {
-	return []Quote{
-		{
-			Content: "The quick brown fox jumps over the lazy dog.",
-			Author:  "Typing Test",
-		},
-		{
-			Content: "To be or not to be, that is the question.",
-			Author:  "William Shakespeare",
-		},
-		{
-			Content: "The only way to do great work is to love what you do.",
-			Author:  "Steve Jobs",
-		},
-		{
-			Content: "In the middle of difficulty lies opportunity.",
-			Author:  "Albert Einstein",
-		},
-		{
-			Content: "Success is not final, failure is not fatal: it is the courage to continue that counts.",
-			Author:  "Winston Churchill",
-		},
+// RandomQuote returns a random quote from the embedded library matching the
+// filter, relaxing the filter if nothing matches
+func RandomQuote(filter Filter) *Quote {
+	library := DefaultLibrary()
+	if quote, err := library.Random(filter); err == nil {
+		return quote
 	}
+	quote, _ := library.Random(Filter{})
+	return quote
 }
 
+// GetFallbackQuotes returns the quotes embedded in the binary for offline use
+func GetFallbackQuotes() []Quote {
+	return DefaultLibrary().All()
+}
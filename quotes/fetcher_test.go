@@ -0,0 +1,220 @@
+package quotes
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testQuoteJSON = `{"_id":"abc","content":"Stand-in quotes never go down.","author":"Test"}`
+
+// stubAPI is a stand-in quote API answering with a scripted list of statuses,
+// repeating the last one once the script runs out
+type stubAPI struct {
+	mu       sync.Mutex
+	statuses []int
+	requests int
+}
+
+func (s *stubAPI) script(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses = statuses
+}
+
+func (s *stubAPI) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	s.requests++
+	s.mu.Unlock()
+
+	w.WriteHeader(status)
+	if status == http.StatusOK {
+		w.Write([]byte(testQuoteJSON))
+	}
+}
+
+func newTestFetcher(t *testing.T, api *stubAPI, retries, threshold int, cooldown time.Duration) *Fetcher {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return NewFetcherWithOptions(FetcherOptions{
+		BaseURL:          server.URL,
+		Timeout:          time.Second,
+		Retries:          retries,
+		FailureThreshold: threshold,
+		Cooldown:         cooldown,
+	})
+}
+
+func TestFetcherRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantRequests int
+		wantFailures int
+	}{
+		{
+			name:         "retries server errors until one succeeds",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantRequests: 2,
+		},
+		{
+			name:         "retries rate limiting",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after the retries",
+			statuses:     []int{http.StatusInternalServerError},
+			wantErr:      true,
+			wantRequests: 3,
+			wantFailures: 1,
+		},
+		{
+			name:         "does not retry or count client errors",
+			statuses:     []int{http.StatusNotFound},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &stubAPI{statuses: tt.statuses}
+			fetcher := newTestFetcher(t, api, 2, 5, time.Minute)
+
+			quote, err := fetcher.FetchRandomQuote()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchRandomQuote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && quote.Content == "" {
+				t.Error("FetchRandomQuote() returned an empty quote")
+			}
+			if got := api.count(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if got := fetcher.Health().ConsecutiveFailures; got != tt.wantFailures {
+				t.Errorf("ConsecutiveFailures = %d, want %d", got, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestFetcherClientErrorsKeepBreakerClosed(t *testing.T) {
+	api := &stubAPI{statuses: []int{http.StatusBadRequest}}
+	fetcher := newTestFetcher(t, api, 0, 2, time.Minute)
+
+	for i := 0; i < 5; i++ {
+		if _, err := fetcher.FetchRandomQuote(); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: breaker opened on client errors", i+1)
+		}
+	}
+	if got := api.count(); got != 5 {
+		t.Errorf("requests = %d, want 5", got)
+	}
+	if state := fetcher.Health().State; state != BreakerClosed {
+		t.Errorf("State = %s, want %s", state, BreakerClosed)
+	}
+}
+
+func TestFetcherBreaker(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+	api := &stubAPI{statuses: []int{http.StatusInternalServerError}}
+
+	var (
+		mu      sync.Mutex
+		changes []BreakerState
+	)
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	fetcher := NewFetcherWithOptions(FetcherOptions{
+		BaseURL:          server.URL,
+		Timeout:          time.Second,
+		FailureThreshold: 2,
+		Cooldown:         cooldown,
+		OnHealthChange: func(health SourceHealth) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, health.State)
+		},
+	})
+
+	// Failures in a row open the breaker, after which no requests are made
+	for i := 0; i < 2; i++ {
+		if _, err := fetcher.FetchRandomQuote(); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: error = %v, want a server error", i+1, err)
+		}
+	}
+	if state := fetcher.Health().State; state != BreakerOpen {
+		t.Fatalf("State = %s, want %s", state, BreakerOpen)
+	}
+	if _, err := fetcher.FetchRandomQuote(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error while open = %v, want ErrCircuitOpen", err)
+	}
+	if got := api.count(); got != 2 {
+		t.Errorf("requests while open = %d, want 2", got)
+	}
+
+	// A failed trial after the cooldown opens it again straight away
+	time.Sleep(cooldown + 10*time.Millisecond)
+	if _, err := fetcher.FetchRandomQuote(); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("trial error = %v, want a server error", err)
+	}
+	if state := fetcher.Health().State; state != BreakerOpen {
+		t.Fatalf("State after failed trial = %s, want %s", state, BreakerOpen)
+	}
+
+	// A successful trial closes it
+	api.script(http.StatusOK)
+	time.Sleep(cooldown + 10*time.Millisecond)
+	if _, err := fetcher.FetchRandomQuote(); err != nil {
+		t.Fatalf("trial error = %v, want none", err)
+	}
+	health := fetcher.Health()
+	if health.State != BreakerClosed || health.ConsecutiveFailures != 0 {
+		t.Errorf("health after recovery = %s with %d failures, want closed with 0", health.State, health.ConsecutiveFailures)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(changes) != len(want) {
+		t.Fatalf("state changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("state changes = %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestBreakerHalfOpenLetsOneTrialThrough(t *testing.T) {
+	breaker := newCircuitBreaker("test", 1, time.Millisecond, nil)
+	breaker.failure(errors.New("down"))
+	time.Sleep(5 * time.Millisecond)
+
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("first trial: allow() = %v, want nil", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second call during trial: allow() = %v, want ErrCircuitOpen", err)
+	}
+	breaker.success()
+	if err := breaker.allow(); err != nil {
+		t.Errorf("after recovery: allow() = %v, want nil", err)
+	}
+}
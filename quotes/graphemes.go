@@ -0,0 +1,63 @@
+package quotes
+
+import (
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Graphemes splits text into its grapheme clusters. A cluster is what a
+// reader sees as one character, such as "é", "ё", "漢" or "👍🏽", which may be
+// several runes; typed text is compared and counted cluster by cluster.
+func Graphemes(s string) []string {
+	clusters := make([]string, 0, len(s))
+	state := -1
+	for s != "" {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// GraphemeCount returns the number of grapheme clusters in text
+func GraphemeCount(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// FirstGrapheme returns the first grapheme cluster of text
+func FirstGrapheme(s string) string {
+	cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(s, -1)
+	return cluster
+}
+
+// TrimLastGrapheme removes the last grapheme cluster from text
+func TrimLastGrapheme(s string) string {
+	clusters := Graphemes(s)
+	if len(clusters) == 0 {
+		return s
+	}
+	return s[:len(s)-len(clusters[len(clusters)-1])]
+}
+
+// ComposeText brings text into the composed form used for quotes, so an
+// accent typed as a separate combining mark matches the accented letter
+func ComposeText(s string) string {
+	return norm.NFC.String(s)
+}
+
+// CompareGraphemes counts how many of the typed grapheme clusters match the
+// prompt at the same position. Clusters typed past the end of the prompt are
+// not counted.
+func CompareGraphemes(prompt, typed string) (correct, total int) {
+	want := Graphemes(prompt)
+	for i, cluster := range Graphemes(typed) {
+		if i >= len(want) {
+			break
+		}
+		total++
+		if cluster == want[i] {
+			correct++
+		}
+	}
+	return correct, total
+}
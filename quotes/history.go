@@ -0,0 +1,258 @@
+package quotes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// LocalPlayer is the history key used for single-player practice
+const LocalPlayer = "local"
+
+// ErrPoolExhausted is returned when every quote matching a filter has
+// already been typed
+var ErrPoolExhausted = errors.New("every matching quote has been typed")
+
+// Fingerprint identifies a quote by its letters and digits, so the same
+// passage is recognised whatever typography or case it arrives with
+func Fingerprint(q *Quote) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(q.Content) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// quoteID returns the quote's ID, or its fingerprint if it has none
+func quoteID(q *Quote) string {
+	if q.ID != "" {
+		return q.ID
+	}
+	return Fingerprint(q)
+}
+
+// History remembers which quotes each player has typed so selection can
+// avoid repeats
+type History struct {
+	path    string
+	players map[string]map[string]time.Time
+	mu      sync.Mutex
+}
+
+// DefaultHistoryPath returns the history file in the user's config directory
+func DefaultHistoryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "typeracer-tui", "history.json"), nil
+}
+
+// NewHistory creates a history kept only in memory
+func NewHistory() *History {
+	return &History{players: make(map[string]map[string]time.Time)}
+}
+
+// OpenHistory loads a history from path; a missing file is an empty history
+func OpenHistory(path string) (*History, error) {
+	history := NewHistory()
+	history.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	if err := json.Unmarshal(data, &history.players); err != nil {
+		return nil, fmt.Errorf("failed to parse history: %w", err)
+	}
+	return history, nil
+}
+
+// Seen reports whether the player has typed the quote before
+func (h *History) Seen(player string, q *Quote) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, seen := h.players[player][quoteID(q)]
+	return seen
+}
+
+// SeenByAny reports whether any of the players has typed the quote before
+func (h *History) SeenByAny(players []string, q *Quote) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := quoteID(q)
+	for _, player := range players {
+		if _, seen := h.players[player][id]; seen {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns how many different quotes the player has typed
+func (h *History) Count(player string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.players[player])
+}
+
+// Record notes that the player has typed the quote
+func (h *History) Record(player string, q *Quote) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.players[player] == nil {
+		h.players[player] = make(map[string]time.Time)
+	}
+	h.players[player][quoteID(q)] = time.Now()
+	return h.save()
+}
+
+// RecordAll notes that each of the players has typed the quote, writing
+// the history file once
+func (h *History) RecordAll(players []string, q *Quote) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for _, player := range players {
+		if h.players[player] == nil {
+			h.players[player] = make(map[string]time.Time)
+		}
+		h.players[player][quoteID(q)] = now
+	}
+	return h.save()
+}
+
+// Forget forgets that the players have typed any of the quotes, leaving
+// the rest of their history alone
+func (h *History) Forget(players []string, quotes []Quote) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, player := range players {
+		for i := range quotes {
+			delete(h.players[player], quoteID(&quotes[i]))
+		}
+		if len(h.players[player]) == 0 {
+			delete(h.players, player)
+		}
+	}
+	return h.save()
+}
+
+// Reset forgets every quote the player has typed
+func (h *History) Reset(player string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.players, player)
+	return h.save()
+}
+
+// ResetAll forgets every player's history
+func (h *History) ResetAll() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.players = make(map[string]map[string]time.Time)
+	return h.save()
+}
+
+// save writes the history file, if there is one
+func (h *History) save() error {
+	if h.path == "" {
+		return nil
+	}
+	if err := WriteJSONFile(h.path, h.players); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// Pick returns a quote none of the players has typed yet. Once they have
+// typed every match, those quotes are forgotten and the cycle starts over.
+func (h *History) Pick(source Source, filter Filter, players ...string) *Quote {
+	seen := func(q *Quote) bool {
+		return h.SeenByAny(players, q)
+	}
+
+	quote, err := PickUnseen(source, filter, seen)
+	if errors.Is(err, ErrPoolExhausted) {
+		h.Forget(players, Matches(source, filter))
+		return Pick(source, filter)
+	}
+	if err != nil {
+		return Pick(source, filter)
+	}
+	return quote
+}
+
+// PickUnseen returns a quote matching the filter that seen rejects as new.
+// Sources that can list their quotes are searched exhaustively and report
+// ErrPoolExhausted once every match has been seen; other sources are
+// sampled a few times before a repeat is accepted.
+func PickUnseen(source Source, filter Filter, seen func(*Quote) bool) (*Quote, error) {
+	// Documents are typed in order, so there is nothing to choose between
+	if _, ok := source.(SequentialSource); ok {
+		return Pick(source, filter), nil
+	}
+
+	if weighted, ok := source.(*WeightedSource); ok && Listable(weighted) {
+		return weighted.pickUnseen(filter, seen)
+	}
+
+	if lister, ok := source.(Lister); ok {
+		matches := lister.Select(filter)
+		if len(matches) == 0 {
+			return Pick(source, filter), nil
+		}
+
+		var unseen []Quote
+		for i := range matches {
+			if !seen(&matches[i]) {
+				unseen = append(unseen, matches[i])
+			}
+		}
+		if len(unseen) == 0 {
+			return nil, ErrPoolExhausted
+		}
+		quote := unseen[rand.Intn(len(unseen))]
+		return &quote, nil
+	}
+
+	const attempts = 10
+	var last *Quote
+	for i := 0; i < attempts; i++ {
+		quote, err := source.Random(filter)
+		if err != nil {
+			break
+		}
+		if !seen(quote) {
+			return quote, nil
+		}
+		last = quote
+	}
+	if last != nil {
+		return last, nil
+	}
+	return PickUnseen(DefaultLibrary(), filter, seen)
+}
@@ -0,0 +1,128 @@
+package quotes
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+//go:embed data/packs/*.json
+var embeddedPacks embed.FS
+
+// Language is the natural language a quote is written in, as an ISO 639-1
+// code
+type Language string
+
+const (
+	LanguageAny Language = ""
+	English     Language = "en"
+	German      Language = "de"
+	Spanish     Language = "es"
+	French      Language = "fr"
+	Russian     Language = "ru"
+)
+
+// DefaultLanguage is assumed for quotes that do not name their language
+const DefaultLanguage = English
+
+// languages lists the languages with an embedded quote pack in the order
+// players cycle through them
+var languages = []Language{English, German, Spanish, French, Russian}
+
+// languageNames maps each language to its English and native names
+var languageNames = map[Language][2]string{
+	English: {"English", "English"},
+	German:  {"German", "Deutsch"},
+	Spanish: {"Spanish", "Español"},
+	French:  {"French", "Français"},
+	Russian: {"Russian", "Русский"},
+}
+
+// Languages returns the languages quote packs are embedded for
+func Languages() []Language {
+	return append([]Language(nil), languages...)
+}
+
+// ParseLanguage parses a language from its code or its English or native
+// name. Codes without an embedded pack are accepted so quote files in other
+// languages can be used.
+func ParseLanguage(s string) (Language, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "any" {
+		return LanguageAny, nil
+	}
+	for language, names := range languageNames {
+		if s == string(language) || s == strings.ToLower(names[0]) || s == strings.ToLower(names[1]) {
+			return language, nil
+		}
+	}
+	if len(s) == 2 && strings.Trim(s, "abcdefghijklmnopqrstuvwxyz") == "" {
+		return Language(s), nil
+	}
+	return LanguageAny, fmt.Errorf("unknown language %q (want a code such as en, de, es, fr or ru)", s)
+}
+
+// Label returns the language's native name, or its code if unknown
+func (l Language) Label() string {
+	if l == LanguageAny {
+		return "any"
+	}
+	if names, ok := languageNames[l]; ok {
+		return names[1]
+	}
+	return string(l)
+}
+
+// Next cycles through the languages with an embedded quote pack
+func (l Language) Next() Language {
+	for i, language := range languages {
+		if language == l {
+			return languages[(i+1)%len(languages)]
+		}
+	}
+	return languages[0]
+}
+
+// LanguageOf returns the language a quote is written in
+func LanguageOf(q *Quote) Language {
+	if q.Language == LanguageAny {
+		return DefaultLanguage
+	}
+	return q.Language
+}
+
+// Pack returns the embedded quotes in a language
+func Pack(language Language) []Quote {
+	return DefaultLibrary().Select(Filter{Language: language})
+}
+
+// loadPacks reads the embedded quote packs, tagging each quote with the
+// language its file is named after
+func loadPacks() ([]Quote, error) {
+	files, err := embeddedPacks.ReadDir("data/packs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote packs: %w", err)
+	}
+
+	var all []Quote
+	for _, file := range files {
+		data, err := embeddedPacks.ReadFile(path.Join("data/packs", file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read quote pack %s: %w", file.Name(), err)
+		}
+		var quotes []Quote
+		if err := json.Unmarshal(data, &quotes); err != nil {
+			return nil, fmt.Errorf("failed to parse quote pack %s: %w", file.Name(), err)
+		}
+		language := Language(strings.TrimSuffix(file.Name(), path.Ext(file.Name())))
+		for i := range quotes {
+			if quotes[i].Language == LanguageAny {
+				quotes[i].Language = language
+			}
+		}
+		all = append(all, quotes...)
+	}
+	return all, nil
+}
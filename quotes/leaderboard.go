@@ -0,0 +1,269 @@
+package quotes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// topResultsPerQuote is how many of the fastest results are kept per quote
+const topResultsPerQuote = 10
+
+// maxTimesPerQuote is how many times are kept per quote for ranks and
+// percentiles; beyond it the times are a uniform sample of every result
+const maxTimesPerQuote = 500
+
+// Result modes
+const (
+	ModeRace     = "race"
+	ModePractice = "practice"
+)
+
+// Result is one player's completed run through a quote
+type Result struct {
+	Player   string    `json:"player"`
+	WPM      float64   `json:"wpm"`
+	Accuracy float64   `json:"accuracy"`
+	Seconds  float64   `json:"seconds"`
+	Mode     string    `json:"mode"`
+	At       time.Time `json:"at"`
+}
+
+// QuoteStats aggregates every result recorded for a quote
+type QuoteStats struct {
+	QuoteID  string  `json:"quote_id"`
+	Author   string  `json:"author"`
+	Preview  string  `json:"preview"`
+	Source   string  `json:"source,omitempty"`
+	Races    int     `json:"races"`
+	TotalWPM float64 `json:"total_wpm"`
+	// Times holds the time of every result, sorted, or a sample of them
+	// once there are more than maxTimesPerQuote
+	Times []float64 `json:"times"`
+	Top   []Result  `json:"top"`
+}
+
+// AverageWPM returns the mean WPM over every recorded result
+func (s QuoteStats) AverageWPM() float64 {
+	if s.Races == 0 {
+		return 0
+	}
+	return s.TotalWPM / float64(s.Races)
+}
+
+// Rank returns where a time of seconds places among every recorded time,
+// starting at 1 for the fastest. Once the times are sampled, ranks among
+// the fastest results are still exact and the rest are estimates.
+func (s QuoteStats) Rank(seconds float64) int {
+	if len(s.Times) == 0 || len(s.Times) >= s.Races {
+		return sort.SearchFloat64s(s.Times, seconds) + 1
+	}
+
+	// Every result faster than the slowest of the top ones is among them
+	faster := 0
+	for _, top := range s.Top {
+		if top.Seconds < seconds {
+			faster++
+		}
+	}
+	if len(s.Top) > 0 && seconds <= s.Top[len(s.Top)-1].Seconds {
+		return faster + 1
+	}
+
+	estimate := float64(sort.SearchFloat64s(s.Times, seconds)) * float64(s.Races) / float64(len(s.Times))
+	return max(int(math.Round(estimate)), faster) + 1
+}
+
+// Percentile returns the share of recorded times that seconds beats or ties
+func (s QuoteStats) Percentile(seconds float64) float64 {
+	if len(s.Times) == 0 {
+		return 100
+	}
+	slower := len(s.Times) - sort.SearchFloat64s(s.Times, seconds)
+	return float64(slower) / float64(len(s.Times)) * 100
+}
+
+// Leaderboard records results per quote in a JSON file
+type Leaderboard struct {
+	path   string
+	quotes map[string]*QuoteStats
+	mu     sync.RWMutex
+}
+
+// DefaultLeaderboardPath returns the leaderboard file in the user's config
+// directory
+func DefaultLeaderboardPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "typeracer-tui", "leaderboard.json"), nil
+}
+
+// NewLeaderboard creates a leaderboard kept only in memory
+func NewLeaderboard() *Leaderboard {
+	return &Leaderboard{quotes: make(map[string]*QuoteStats)}
+}
+
+// OpenLeaderboard loads a leaderboard from path; a missing file is empty
+func OpenLeaderboard(path string) (*Leaderboard, error) {
+	leaderboard := NewLeaderboard()
+	leaderboard.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return leaderboard, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard: %w", err)
+	}
+	if err := json.Unmarshal(data, &leaderboard.quotes); err != nil {
+		return nil, fmt.Errorf("failed to parse leaderboard: %w", err)
+	}
+	// Files saved before times were capped may hold every result
+	for _, stats := range leaderboard.quotes {
+		stats.Times = sampleTimes(stats.Times, maxTimesPerQuote)
+	}
+	return leaderboard, nil
+}
+
+// Record adds a result for the quote. Generated text never repeats, so it
+// is not recorded.
+func (l *Leaderboard) Record(q *Quote, result Result) error {
+	if q.Source == GeneratedSource || result.Seconds <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := quoteID(q)
+	stats, exists := l.quotes[id]
+	if !exists {
+		stats = &QuoteStats{
+			QuoteID: id,
+			Author:  q.Author,
+			Preview: preview(q.Content),
+			Source:  q.Source,
+		}
+		l.quotes[id] = stats
+	}
+
+	if result.At.IsZero() {
+		result.At = time.Now()
+	}
+	stats.Races++
+	stats.TotalWPM += result.WPM
+
+	stats.addTime(result.Seconds)
+
+	stats.Top = append(stats.Top, result)
+	sort.SliceStable(stats.Top, func(a, b int) bool {
+		return stats.Top[a].Seconds < stats.Top[b].Seconds
+	})
+	if len(stats.Top) > topResultsPerQuote {
+		stats.Top = stats.Top[:topResultsPerQuote]
+	}
+
+	return l.save()
+}
+
+// Stats returns the results recorded for a quote ID
+func (l *Leaderboard) Stats(quoteID string) (QuoteStats, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	stats, exists := l.quotes[quoteID]
+	if !exists {
+		return QuoteStats{QuoteID: quoteID}, false
+	}
+	return stats.copy(), true
+}
+
+// MostRaced returns up to n quotes with the most recorded results
+func (l *Leaderboard) MostRaced(n int) []QuoteStats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	all := make([]QuoteStats, 0, len(l.quotes))
+	for _, stats := range l.quotes {
+		all = append(all, stats.copy())
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Races != all[j].Races {
+			return all[i].Races > all[j].Races
+		}
+		return all[i].QuoteID < all[j].QuoteID
+	})
+	if n > 0 && len(all) > n {
+		all = all[:n]
+	}
+	return all
+}
+
+// addTime records a result's time, keeping the times sorted. Past
+// maxTimesPerQuote a time replaces a random one with the chance that keeps
+// the times a uniform sample of every result.
+func (s *QuoteStats) addTime(seconds float64) {
+	if len(s.Times) >= maxTimesPerQuote {
+		if rand.Intn(s.Races) >= maxTimesPerQuote {
+			return
+		}
+		i := rand.Intn(len(s.Times))
+		s.Times = append(s.Times[:i], s.Times[i+1:]...)
+	}
+
+	i := sort.SearchFloat64s(s.Times, seconds)
+	s.Times = append(s.Times, 0)
+	copy(s.Times[i+1:], s.Times[i:])
+	s.Times[i] = seconds
+}
+
+// sampleTimes thins sorted times evenly down to n
+func sampleTimes(times []float64, n int) []float64 {
+	if len(times) <= n {
+		return times
+	}
+	sampled := make([]float64, n)
+	for i := range sampled {
+		sampled[i] = times[i*len(times)/n]
+	}
+	return sampled
+}
+
+// copy returns a deep copy that is safe to read without the lock
+func (s *QuoteStats) copy() QuoteStats {
+	c := *s
+	c.Times = append([]float64(nil), s.Times...)
+	c.Top = append([]Result(nil), s.Top...)
+	return c
+}
+
+// save writes the leaderboard file, if there is one
+func (l *Leaderboard) save() error {
+	if l.path == "" {
+		return nil
+	}
+	if err := WriteJSONFile(l.path, l.quotes); err != nil {
+		return fmt.Errorf("failed to write leaderboard: %w", err)
+	}
+	return nil
+}
+
+// preview shortens quote text for listings
+func preview(content string) string {
+	const maxPreview = 60
+	runes := []rune(strings.Join(strings.Fields(content), " "))
+	if len(runes) <= maxPreview {
+		return string(runes)
+	}
+	return string(runes[:maxPreview-3]) + "..."
+}
@@ -0,0 +1,62 @@
+package quotes
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLeaderboardRanks(t *testing.T) {
+	quote := &Quote{ID: "q1", Content: "A quote raced many times."}
+
+	tests := []struct {
+		name        string
+		races       int
+		seconds     float64
+		wantTimes   int
+		wantRank    int
+		rankSlack   int
+		wantPercent float64
+		pctSlack    float64
+	}{
+		{name: "every time kept", races: 100, seconds: 50.5, wantTimes: 100, wantRank: 51, wantPercent: 50},
+		{name: "fastest is exact", races: 2000, seconds: 1.5, wantTimes: maxTimesPerQuote, wantRank: 2, wantPercent: 99.95, pctSlack: 0.5},
+		{name: "sampled", races: 2000, seconds: 1000.5, wantTimes: maxTimesPerQuote, wantRank: 1001, rankSlack: 200, wantPercent: 50, pctSlack: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaderboard := NewLeaderboard()
+			for i := 1; i <= tt.races; i++ {
+				if err := leaderboard.Record(quote, Result{Player: "p", Seconds: float64(i)}); err != nil {
+					t.Fatalf("Record() error: %v", err)
+				}
+			}
+
+			stats, _ := leaderboard.Stats(quote.ID)
+			if stats.Races != tt.races {
+				t.Errorf("Races = %d, want %d", stats.Races, tt.races)
+			}
+			if len(stats.Times) != tt.wantTimes {
+				t.Errorf("kept %d times, want %d", len(stats.Times), tt.wantTimes)
+			}
+			for i := 1; i < len(stats.Times); i++ {
+				if stats.Times[i] < stats.Times[i-1] {
+					t.Fatalf("times are not sorted at %d", i)
+				}
+			}
+			if rank := stats.Rank(tt.seconds); abs(rank-tt.wantRank) > tt.rankSlack {
+				t.Errorf("Rank(%v) = %d, want %d±%d", tt.seconds, rank, tt.wantRank, tt.rankSlack)
+			}
+			if pct := stats.Percentile(tt.seconds); math.Abs(pct-tt.wantPercent) > tt.pctSlack {
+				t.Errorf("Percentile(%v) = %v, want %v±%v", tt.seconds, pct, tt.wantPercent, tt.pctSlack)
+			}
+		})
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
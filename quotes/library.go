@@ -0,0 +1,201 @@
+package quotes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+//go:embed data/quotes.json
+var embeddedQuotes []byte
+
+// LengthBucket groups quotes by how long they take to type
+type LengthBucket string
+
+const (
+	LengthAny    LengthBucket = ""
+	LengthShort  LengthBucket = "short"
+	LengthMedium LengthBucket = "medium"
+	LengthLong   LengthBucket = "long"
+)
+
+// Length bucket boundaries in characters
+const (
+	shortMaxLength  = 80
+	mediumMaxLength = 200
+)
+
+// Difficulty is a coarse rating of how hard a quote is to type
+type Difficulty string
+
+const (
+	DifficultyAny    Difficulty = ""
+	DifficultyEasy   Difficulty = "easy"
+	DifficultyMedium Difficulty = "medium"
+	DifficultyHard   Difficulty = "hard"
+)
+
+// Filter describes which quotes a selection may return
+type Filter struct {
+	Length     LengthBucket
+	Difficulty Difficulty
+	Tags       []string
+	Language   Language
+}
+
+// ParseLengthBucket parses a length bucket name
+func ParseLengthBucket(s string) (LengthBucket, error) {
+	switch bucket := LengthBucket(strings.ToLower(s)); bucket {
+	case LengthAny, LengthShort, LengthMedium, LengthLong:
+		return bucket, nil
+	}
+	return LengthAny, fmt.Errorf("unknown length %q (want short, medium or long)", s)
+}
+
+// ParseDifficulty parses a difficulty name
+func ParseDifficulty(s string) (Difficulty, error) {
+	switch difficulty := Difficulty(strings.ToLower(s)); difficulty {
+	case DifficultyAny, DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return difficulty, nil
+	}
+	return DifficultyAny, fmt.Errorf("unknown difficulty %q (want easy, medium or hard)", s)
+}
+
+// LengthBucketOf returns the length bucket a quote falls into
+func LengthBucketOf(q *Quote) LengthBucket {
+	n := GraphemeCount(q.Content)
+	switch {
+	case n <= shortMaxLength:
+		return LengthShort
+	case n <= mediumMaxLength:
+		return LengthMedium
+	default:
+		return LengthLong
+	}
+}
+
+// Identify fills in a quote's ID, source and length category where they
+// are missing. The ID is the content fingerprint, so the same passage gets
+// the same ID from every source.
+func Identify(q *Quote, source string) {
+	if q.ID == "" {
+		q.ID = Fingerprint(q)
+	}
+	if q.Source == "" {
+		q.Source = source
+	}
+	q.Length = GraphemeCount(q.Content)
+	q.Category = LengthBucketOf(q)
+	q.Difficulty = ScoreDifficulty(q).Level()
+}
+
+// Matches reports whether a quote satisfies the filter
+func (f Filter) Matches(q *Quote) bool {
+	if f.Length != LengthAny && LengthBucketOf(q) != f.Length {
+		return false
+	}
+	if f.Difficulty != DifficultyAny && DifficultyOf(q) != f.Difficulty {
+		return false
+	}
+	// Code reads the same in every language
+	if f.Language != LanguageAny && !q.Code && LanguageOf(q) != f.Language {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !q.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasTag reports whether the quote carries the given tag
+func (q *Quote) HasTag(tag string) bool {
+	for _, t := range q.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Library is an in-memory collection of quotes
+type Library struct {
+	quotes []Quote
+}
+
+var (
+	defaultLibrary     *Library
+	defaultLibraryOnce sync.Once
+)
+
+// NewLibrary creates a library from the given quotes
+func NewLibrary(quotes []Quote) *Library {
+	library := &Library{quotes: make([]Quote, 0, len(quotes))}
+	for _, quote := range quotes {
+		if strings.TrimSpace(quote.Content) == "" {
+			continue
+		}
+		Identify(&quote, "")
+		library.quotes = append(library.quotes, quote)
+	}
+	return library
+}
+
+// DefaultLibrary returns the quote library embedded in the binary: the
+// English quotes and every language pack
+func DefaultLibrary() *Library {
+	defaultLibraryOnce.Do(func() {
+		var quotes []Quote
+		if err := json.Unmarshal(embeddedQuotes, &quotes); err != nil {
+			panic(fmt.Sprintf("quotes: embedded library is invalid: %v", err))
+		}
+		packs, err := loadPacks()
+		if err != nil {
+			panic(fmt.Sprintf("quotes: embedded quote packs are invalid: %v", err))
+		}
+		quotes = append(quotes, packs...)
+		for i := range quotes {
+			quotes[i].Source = "embedded"
+		}
+		defaultLibrary = NewLibrary(quotes)
+	})
+	return defaultLibrary
+}
+
+// Len returns the number of quotes in the library
+func (l *Library) Len() int {
+	return len(l.quotes)
+}
+
+// All returns a copy of every quote in the library
+func (l *Library) All() []Quote {
+	quotes := make([]Quote, len(l.quotes))
+	copy(quotes, l.quotes)
+	return quotes
+}
+
+// Select returns every quote matching the filter
+func (l *Library) Select(filter Filter) []Quote {
+	var matches []Quote
+	for i := range l.quotes {
+		if filter.Matches(&l.quotes[i]) {
+			matches = append(matches, l.quotes[i])
+		}
+	}
+	return matches
+}
+
+// Random returns a random quote matching the filter
+func (l *Library) Random(filter Filter) (*Quote, error) {
+	matches := l.Select(filter)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no quotes match the requested filter")
+	}
+
+	quote := matches[rand.Intn(len(matches))]
+	return &quote, nil
+}
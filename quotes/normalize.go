@@ -0,0 +1,148 @@
+package quotes
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeOptions controls how quote text is cleaned up before racing
+type NormalizeOptions struct {
+	// FoldTypography replaces curly quotes, dashes, ellipses and unusual
+	// spaces with their plain keyboard equivalents
+	FoldTypography bool
+	// FoldDiacritics replaces accented Latin letters with unaccented ones
+	FoldDiacritics bool
+	// CollapseWhitespace trims the text and joins runs of whitespace into a
+	// single space
+	CollapseWhitespace bool
+	// Lowercase converts the text to lower case
+	Lowercase bool
+	// StripPunctuation removes punctuation and symbols
+	StripPunctuation bool
+}
+
+// Normalizer rewrites quote text so that it can be typed on a normal keyboard
+type Normalizer struct {
+	options NormalizeOptions
+}
+
+// NewNormalizer creates a normalizer with the given options
+func NewNormalizer(options NormalizeOptions) *Normalizer {
+	return &Normalizer{options: options}
+}
+
+// DefaultNormalizer folds typography and collapses whitespace, leaving
+// letters, case and punctuation alone
+func DefaultNormalizer() *Normalizer {
+	return NewNormalizer(NormalizeOptions{
+		FoldTypography:     true,
+		CollapseWhitespace: true,
+	})
+}
+
+// Options returns the normalizer's options
+func (n *Normalizer) Options() NormalizeOptions {
+	return n.options
+}
+
+// typographyReplacements maps characters that are hard to type to their
+// keyboard equivalents
+var typographyReplacements = map[rune]string{
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'", '´': "'",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '″': "\"", '«': "\"", '»': "\"",
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+	'…': "...", '•': "*",
+	'\u00a0': " ", '\u2002': " ", '\u2003': " ", '\u2007': " ", '\u2009': " ", '\u200a': " ", '\u202f': " ", '\u3000': " ",
+	'\u200b': "", '\ufeff': "", '\u00ad': "",
+	// Zero-width joiners are kept: they bind emoji sequences and shape
+	// letters in scripts such as Persian and Hindi
+}
+
+// diacriticReplacements maps accented Latin letters to plain ASCII
+var diacriticReplacements = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ą': "a",
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Ā': "A", 'Ą': "A",
+	'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ß': "ss",
+	'ç': "c", 'ć': "c", 'č': "c", 'Ç': "C", 'Ć': "C", 'Č': "C",
+	'ď': "d", 'đ': "d", 'Ď': "D", 'Đ': "D",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ę': "E", 'Ě': "E",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ī': "I",
+	'ł': "l", 'Ł': "L",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'Ñ': "N", 'Ń': "N", 'Ň': "N",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O", 'Ō': "O", 'Ő': "O",
+	'ř': "r", 'Ř': "R", 'ś': "s", 'š': "s", 'Ś': "S", 'Š': "S", 'ť': "t", 'Ť': "T",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ū': "U", 'Ů': "U", 'Ű': "U",
+	'ý': "y", 'ÿ': "y", 'Ý': "Y", 'ź': "z", 'ż': "z", 'ž': "z", 'Ź': "Z", 'Ż': "Z", 'Ž': "Z",
+}
+
+// String normalizes a piece of text. Accented letters are always composed
+// so they compare equal to what a keyboard produces.
+func (n *Normalizer) String(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range ComposeText(s) {
+		replacement := string(r)
+		if folded, ok := typographyReplacements[r]; ok && n.options.FoldTypography {
+			replacement = folded
+		} else if folded, ok := diacriticReplacements[r]; ok && n.options.FoldDiacritics {
+			replacement = folded
+		}
+
+		// Folded quotes, dashes and ellipses are punctuation too
+		for _, c := range replacement {
+			if n.options.StripPunctuation && (unicode.IsPunct(c) || unicode.IsSymbol(c)) {
+				continue
+			}
+			b.WriteRune(c)
+		}
+	}
+
+	text := b.String()
+	if n.options.Lowercase {
+		text = strings.ToLower(text)
+	}
+	if n.options.CollapseWhitespace {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	return text
+}
+
+// Code normalizes a source code snippet. Only typography is folded; case,
+// punctuation, newlines and indentation are significant in code, so the
+// other options are ignored and just trailing spaces are trimmed.
+func (n *Normalizer) Code(s string) string {
+	lines := strings.Split(strings.ReplaceAll(ComposeText(s), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if n.options.FoldTypography {
+			var b strings.Builder
+			for _, r := range line {
+				if replacement, ok := typographyReplacements[r]; ok {
+					b.WriteString(replacement)
+					continue
+				}
+				b.WriteRune(r)
+			}
+			line = b.String()
+		}
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Quote returns a normalized copy of the quote
+func (n *Normalizer) Quote(q *Quote) *Quote {
+	normalized := *q
+	if q.Code {
+		normalized.Content = n.Code(q.Content)
+	} else {
+		normalized.Content = n.String(q.Content)
+	}
+	normalized.Length = GraphemeCount(normalized.Content)
+	normalized.Category = LengthBucketOf(&normalized)
+	normalized.Difficulty = ScoreDifficulty(&normalized).Level()
+	return &normalized
+}
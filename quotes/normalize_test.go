@@ -0,0 +1,84 @@
+package quotes
+
+import "testing"
+
+func TestNormalizerString(t *testing.T) {
+	tests := []struct {
+		name    string
+		options NormalizeOptions
+		in      string
+		want    string
+	}{
+		{
+			name:    "folds typography",
+			options: NormalizeOptions{FoldTypography: true},
+			in:      "don’t stop… “now” — ok",
+			want:    "don't stop... \"now\" - ok",
+		},
+		{
+			name:    "strips folded punctuation",
+			options: NormalizeOptions{FoldTypography: true, StripPunctuation: true, CollapseWhitespace: true},
+			in:      "don’t stop… “now” — ok",
+			want:    "dont stop now ok",
+		},
+		{
+			name:    "keeps backticks and middle dots",
+			options: NormalizeOptions{FoldTypography: true},
+			in:      "a `b` · c",
+			want:    "a `b` · c",
+		},
+		{
+			name:    "folds diacritics",
+			options: NormalizeOptions{FoldDiacritics: true},
+			in:      "Crème brûlée, Łódź",
+			want:    "Creme brulee, Lodz",
+		},
+		{
+			name:    "composes accents",
+			options: NormalizeOptions{},
+			in:      "café",
+			want:    "café",
+		},
+		{
+			name:    "collapses whitespace and lowercases",
+			options: NormalizeOptions{CollapseWhitespace: true, Lowercase: true},
+			in:      "  Hello\n\tWORLD  ",
+			want:    "hello world",
+		},
+		{
+			name:    "keeps zero-width joiners",
+			options: NormalizeOptions{FoldTypography: true},
+			in:      "👩‍💻​",
+			want:    "👩‍💻",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewNormalizer(tt.options).String(tt.in); got != tt.want {
+				t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizerCode(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"keeps indentation", "func f() {\n\treturn\n}", "func f() {\n\treturn\n}"},
+		{"trims trailing spaces and blank edges", "\nx := 1  \r\ny := 2\t\n\n", "x := 1\ny := 2"},
+		{"folds curly quotes", "s := “hi”", "s := \"hi\""},
+		{"keeps backticks", "s := `raw`", "s := `raw`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultNormalizer().Code(tt.in); got != tt.want {
+				t.Errorf("Code(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package quotes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Vote is a player's opinion of a quote
+type Vote string
+
+// Votes a player can cast
+const (
+	VoteUp   Vote = "up"
+	VoteDown Vote = "down"
+	VoteFlag Vote = "flag"
+)
+
+// Rating thresholds
+const (
+	// hideFlags is how many voters must flag a quote to take it out of
+	// rotation, unless more of them liked it than flagged it
+	hideFlags = 2
+	// minRatingWeight keeps even disliked quotes coming up now and then
+	minRatingWeight = 0.1
+)
+
+// Rating aggregates the votes cast on one quote
+type Rating struct {
+	Up    int `json:"up"`
+	Down  int `json:"down"`
+	Flags int `json:"flags"`
+	// Votes holds the vote of each voter, one per voter key
+	Votes map[string]Vote `json:"votes"`
+}
+
+// Hidden reports whether enough players flagged the quote as broken to stop
+// it being picked
+func (r Rating) Hidden() bool {
+	return r.Flags >= hideFlags && r.Flags > r.Up
+}
+
+// Weight returns how likely the quote is to be kept when drawn, from 0 for
+// hidden quotes to 1 for quotes nobody dislikes. Flags count as downvotes.
+func (r Rating) Weight() float64 {
+	if r.Hidden() {
+		return 0
+	}
+	negative := r.Down + r.Flags
+	if negative == 0 {
+		return 1
+	}
+	return max(float64(r.Up+1)/float64(r.Up+negative+1), minRatingWeight)
+}
+
+// Ratings stores players' votes per quote in a JSON file
+type Ratings struct {
+	path   string
+	quotes map[string]*Rating
+	mu     sync.RWMutex
+}
+
+// DefaultRatingsPath returns the ratings file in the user's config directory
+func DefaultRatingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "typeracer-tui", "ratings.json"), nil
+}
+
+// NewRatings creates ratings kept only in memory
+func NewRatings() *Ratings {
+	return &Ratings{quotes: make(map[string]*Rating)}
+}
+
+// OpenRatings loads ratings from path; a missing file is empty
+func OpenRatings(path string) (*Ratings, error) {
+	ratings := NewRatings()
+	ratings.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ratings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ratings: %w", err)
+	}
+	if err := json.Unmarshal(data, &ratings.quotes); err != nil {
+		return nil, fmt.Errorf("failed to parse ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+// Vote records a voter's vote on a quote, replacing any earlier vote of
+// theirs. The voter key should identify a person rather than a name anyone
+// can claim, since flags from distinct voters hide a quote. Generated text
+// never repeats, so it is not rated.
+func (r *Ratings) Vote(q *Quote, voter string, vote Vote) error {
+	if q.Source == GeneratedSource {
+		return nil
+	}
+	if voter == "" {
+		return fmt.Errorf("vote has no voter")
+	}
+
+	switch vote {
+	case VoteUp, VoteDown, VoteFlag:
+	default:
+		return fmt.Errorf("invalid vote %q", vote)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := quoteID(q)
+	rating, exists := r.quotes[id]
+	if !exists {
+		rating = &Rating{Votes: make(map[string]Vote)}
+		r.quotes[id] = rating
+	}
+	rating.Votes[voter] = vote
+
+	rating.Up, rating.Down, rating.Flags = 0, 0, 0
+	for _, cast := range rating.Votes {
+		switch cast {
+		case VoteUp:
+			rating.Up++
+		case VoteDown:
+			rating.Down++
+		case VoteFlag:
+			rating.Flags++
+		}
+	}
+
+	if r.path == "" {
+		return nil
+	}
+	if err := WriteJSONFile(r.path, r.quotes); err != nil {
+		return fmt.Errorf("failed to write ratings: %w", err)
+	}
+	return nil
+}
+
+// Get returns the vote counts for a quote, without who cast them
+func (r *Ratings) Get(quoteID string) Rating {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if rating, exists := r.quotes[quoteID]; exists {
+		return Rating{Up: rating.Up, Down: rating.Down, Flags: rating.Flags}
+	}
+	return Rating{}
+}
+
+// VoteOf returns the vote a voter cast on a quote, if any
+func (r *Ratings) VoteOf(quoteID, voter string) (Vote, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if rating, exists := r.quotes[quoteID]; exists {
+		vote, voted := rating.Votes[voter]
+		return vote, voted
+	}
+	return "", false
+}
+
+// Keep decides at random, in proportion to its rating, whether a drawn quote
+// should be used. Nil ratings keep every quote.
+func (r *Ratings) Keep(q *Quote) bool {
+	if r == nil {
+		return true
+	}
+	weight := r.Get(quoteID(q)).Weight()
+	return weight >= 1 || rand.Float64() < weight
+}
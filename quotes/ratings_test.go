@@ -0,0 +1,61 @@
+package quotes
+
+import "testing"
+
+func TestRatingsVote(t *testing.T) {
+	quote := &Quote{ID: "q1", Content: "A quote worth flagging."}
+
+	tests := []struct {
+		name       string
+		votes      [][2]string
+		wantFlags  int
+		wantHidden bool
+	}{
+		{
+			name:      "one voter flagging twice counts once",
+			votes:     [][2]string{{"SHA256:a", "flag"}, {"SHA256:a", "flag"}},
+			wantFlags: 1,
+		},
+		{
+			name:       "distinct voters hide a quote",
+			votes:      [][2]string{{"SHA256:a", "flag"}, {"SHA256:b", "flag"}},
+			wantFlags:  2,
+			wantHidden: true,
+		},
+		{
+			name:      "likes outweigh flags",
+			votes:     [][2]string{{"SHA256:a", "flag"}, {"SHA256:b", "flag"}, {"SHA256:c", "up"}, {"SHA256:d", "up"}},
+			wantFlags: 2,
+		},
+		{
+			name:      "a changed vote replaces the flag",
+			votes:     [][2]string{{"SHA256:a", "flag"}, {"SHA256:b", "flag"}, {"SHA256:b", "down"}},
+			wantFlags: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratings := NewRatings()
+			for _, vote := range tt.votes {
+				if err := ratings.Vote(quote, vote[0], Vote(vote[1])); err != nil {
+					t.Fatalf("Vote(%q, %q) error: %v", vote[0], vote[1], err)
+				}
+			}
+			rating := ratings.Get(quote.ID)
+			if rating.Flags != tt.wantFlags {
+				t.Errorf("Flags = %d, want %d", rating.Flags, tt.wantFlags)
+			}
+			if rating.Hidden() != tt.wantHidden {
+				t.Errorf("Hidden() = %v, want %v", rating.Hidden(), tt.wantHidden)
+			}
+		})
+	}
+}
+
+func TestRatingsVoteWithoutVoter(t *testing.T) {
+	ratings := NewRatings()
+	if err := ratings.Vote(&Quote{ID: "q1", Content: "Text."}, "", VoteFlag); err == nil {
+		t.Error("Vote without a voter succeeded")
+	}
+}
@@ -0,0 +1,191 @@
+package quotes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// errNoMatch is returned when no quote satisfies a request
+var errNoMatch = errors.New("could not find any matching quotes")
+
+// serverSamples is how many quotes are drawn from a source that cannot be
+// searched when looking for one that matches a request
+const serverSamples = 50
+
+// apiQuote is the JSON shape of a quote served by the quotable API
+type apiQuote struct {
+	ID      string   `json:"_id"`
+	Content string   `json:"content"`
+	Author  string   `json:"author"`
+	Tags    []string `json:"tags"`
+	Length  int      `json:"length"`
+	// Language is not part of the quotable API; it is sent so that other
+	// servers can race in the right language
+	Language Language `json:"language,omitempty"`
+}
+
+// apiError is the JSON shape of a quotable API error
+type apiError struct {
+	StatusCode    int    `json:"statusCode"`
+	StatusMessage string `json:"statusMessage"`
+}
+
+// randomQuery holds the filters of a /random request
+type randomQuery struct {
+	minLength int
+	maxLength int
+	// tags lists alternatives, each a set of tags that must all be present
+	tags     [][]string
+	language Language
+}
+
+// NewServer returns an HTTP handler serving quotes from source in the
+// quotable API format, so a Fetcher can use it in place of api.quotable.io.
+// GET /random accepts minLength, maxLength and tags, where tags are
+// separated by ',' for all-of and '|' for any-of, and a language code.
+func NewServer(source Source) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /random", func(w http.ResponseWriter, r *http.Request) {
+		query, err := parseRandomQuery(r)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		quote, err := query.pick(source)
+		if errors.Is(err, errNoMatch) {
+			writeAPIError(w, http.StatusNotFound, err)
+			return
+		}
+		if err != nil {
+			writeAPIError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		tags := quote.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		writeJSON(w, http.StatusOK, apiQuote{
+			ID:       quoteID(quote),
+			Content:  quote.Content,
+			Author:   quote.Author,
+			Tags:     tags,
+			Length:   GraphemeCount(quote.Content),
+			Language: LanguageOf(quote),
+		})
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	return mux
+}
+
+// parseRandomQuery reads the filters of a /random request
+func parseRandomQuery(r *http.Request) (randomQuery, error) {
+	var query randomQuery
+	values := r.URL.Query()
+
+	for name, target := range map[string]*int{"minLength": &query.minLength, "maxLength": &query.maxLength} {
+		if value := values.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return query, fmt.Errorf("invalid %s: %q", name, value)
+			}
+			*target = n
+		}
+	}
+
+	if value := values.Get("language"); value != "" {
+		language, err := ParseLanguage(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid language: %q", value)
+		}
+		query.language = language
+	}
+
+	if tags := values.Get("tags"); tags != "" {
+		for _, alternative := range strings.Split(tags, "|") {
+			var all []string
+			for _, tag := range strings.Split(alternative, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					all = append(all, tag)
+				}
+			}
+			query.tags = append(query.tags, all)
+		}
+	}
+	return query, nil
+}
+
+// matches reports whether a quote satisfies the request
+func (q randomQuery) matches(quote *Quote) bool {
+	length := GraphemeCount(quote.Content)
+	if length < q.minLength || (q.maxLength > 0 && length > q.maxLength) {
+		return false
+	}
+	if q.language != LanguageAny && LanguageOf(quote) != q.language {
+		return false
+	}
+	if len(q.tags) == 0 {
+		return true
+	}
+	for _, all := range q.tags {
+		if (Filter{Tags: all}).Matches(quote) {
+			return true
+		}
+	}
+	return false
+}
+
+// pick returns a random quote from source satisfying the request, searching
+// listable sources exhaustively and sampling other sources
+func (q randomQuery) pick(source Source) (*Quote, error) {
+	if Listable(source) {
+		var matches []Quote
+		for _, quote := range Matches(source, Filter{}) {
+			if q.matches(&quote) {
+				matches = append(matches, quote)
+			}
+		}
+		if len(matches) == 0 {
+			return nil, errNoMatch
+		}
+		return &matches[rand.Intn(len(matches))], nil
+	}
+
+	for i := 0; i < serverSamples; i++ {
+		quote, err := source.Random(Filter{})
+		if err != nil {
+			return nil, fmt.Errorf("quote source failed: %w", err)
+		}
+		if q.matches(quote) {
+			return quote, nil
+		}
+	}
+	return nil, errNoMatch
+}
+
+// writeAPIError writes an error in the quotable API format, whose status
+// messages are capitalized
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	message := err.Error()
+	if r, size := utf8.DecodeRuneInString(message); size > 0 {
+		message = string(unicode.ToUpper(r)) + message[size:]
+	}
+	writeJSON(w, status, apiError{StatusCode: status, StatusMessage: message})
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,75 @@
+package quotes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServerRandom(t *testing.T) {
+	// The only science quote sits in the lightly weighted backend
+	source := NewWeightedSource().
+		Add(NewLibrary([]Quote{{Content: "A quote about work.", Tags: []string{"work"}}}), 1000).
+		Add(NewLibrary([]Quote{{Content: "A quote about science.", Tags: []string{"science"}}}), 0.001)
+	server := httptest.NewServer(NewServer(source))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		query       string
+		wantStatus  int
+		wantContent string
+		wantMessage string
+	}{
+		{
+			name:        "finds a match in any backend of a mix",
+			query:       "?tags=science",
+			wantStatus:  http.StatusOK,
+			wantContent: "A quote about science.",
+		},
+		{
+			name:        "reports no match",
+			query:       "?tags=poetry",
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "Could not find any matching quotes",
+		},
+		{
+			name:        "rejects a bad length",
+			query:       "?maxLength=short",
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: `Invalid maxLength: "short"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat so a sampled draw cannot pass by luck
+			for i := 0; i < 20; i++ {
+				resp, err := http.Get(server.URL + "/random" + tt.query)
+				if err != nil {
+					t.Fatalf("GET /random error: %v", err)
+				}
+				var body struct {
+					apiQuote
+					apiError
+				}
+				err = json.NewDecoder(resp.Body).Decode(&body)
+				resp.Body.Close()
+				if err != nil {
+					t.Fatalf("decoding response: %v", err)
+				}
+
+				if resp.StatusCode != tt.wantStatus {
+					t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+				if body.Content != tt.wantContent {
+					t.Errorf("content = %q, want %q", body.Content, tt.wantContent)
+				}
+				if body.StatusMessage != tt.wantMessage {
+					t.Errorf("statusMessage = %q, want %q", body.StatusMessage, tt.wantMessage)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package quotes
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Source provides quotes for practice and races
+type Source interface {
+	// Random returns a random quote matching the filter
+	Random(filter Filter) (*Quote, error)
+}
+
+// Lister is a source that can list every quote matching a filter, so
+// selection from it can be exhaustive
+type Lister interface {
+	Source
+	Select(filter Filter) []Quote
+}
+
+// Listable reports whether every quote a source can serve can be listed.
+// A weighted mix is listable when all of its backends are.
+func Listable(source Source) bool {
+	if weighted, ok := source.(*WeightedSource); ok {
+		for _, entry := range weighted.entries {
+			if !Listable(entry.source) {
+				return false
+			}
+		}
+		return len(weighted.entries) > 0
+	}
+	_, ok := source.(Lister)
+	return ok
+}
+
+// Matches returns every quote a listable source holds matching the filter,
+// or nil if the source cannot list its quotes
+func Matches(source Source, filter Filter) []Quote {
+	if weighted, ok := source.(*WeightedSource); ok && Listable(weighted) {
+		var matches []Quote
+		for _, entry := range weighted.entries {
+			matches = append(matches, Matches(entry.source, filter)...)
+		}
+		return matches
+	}
+	if lister, ok := source.(Lister); ok {
+		return lister.Select(filter)
+	}
+	return nil
+}
+
+// DefaultSource returns the source used when nothing else is configured
+func DefaultSource() Source {
+	return DefaultLibrary()
+}
+
+// Pick returns a quote from the source, relaxing the filter and finally
+// falling back to the embedded library so callers always get something to type
+func Pick(source Source, filter Filter) *Quote {
+	if quote, err := source.Random(filter); err == nil {
+		return quote
+	}
+	if quote, err := source.Random(Filter{}); err == nil {
+		return quote
+	}
+	return RandomQuote(filter)
+}
+
+// SequenceSource serves a fixed list of quotes in order, wrapping around at
+// the end; it is deterministic and intended for tests and scripted setups
+type SequenceSource struct {
+	quotes []Quote
+	next   int
+	mu     sync.Mutex
+}
+
+// NewSequenceSource creates a source that cycles through the given quotes
+func NewSequenceSource(quotes ...Quote) *SequenceSource {
+	return &SequenceSource{quotes: quotes}
+}
+
+// Random returns the next quote in the sequence that matches the filter
+func (s *SequenceSource) Random(filter Filter) (*Quote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < len(s.quotes); i++ {
+		quote := s.quotes[s.next]
+		s.next = (s.next + 1) % len(s.quotes)
+		if filter.Matches(&quote) {
+			return &quote, nil
+		}
+	}
+	return nil, fmt.Errorf("no quotes match the requested filter")
+}
+
+// WeightedSource mixes several sources, choosing each with a probability
+// proportional to its weight and falling through to the others on failure
+type WeightedSource struct {
+	entries []weightedEntry
+	total   float64
+}
+
+type weightedEntry struct {
+	source Source
+	weight float64
+}
+
+// NewWeightedSource creates an empty weighted source
+func NewWeightedSource() *WeightedSource {
+	return &WeightedSource{}
+}
+
+// Add registers a source with the given weight; non-positive weights are ignored
+func (w *WeightedSource) Add(source Source, weight float64) *WeightedSource {
+	if weight > 0 {
+		w.entries = append(w.entries, weightedEntry{source: source, weight: weight})
+		w.total += weight
+	}
+	return w
+}
+
+// Random picks a source by weight and returns one of its quotes
+func (w *WeightedSource) Random(filter Filter) (*Quote, error) {
+	if len(w.entries) == 0 {
+		return nil, fmt.Errorf("weighted source has no backends")
+	}
+
+	var errs []error
+	for _, entry := range w.order() {
+		quote, err := entry.source.Random(filter)
+		if err == nil {
+			return quote, nil
+		}
+		errs = append(errs, err)
+	}
+
+	return nil, fmt.Errorf("all quote sources failed: %w", errors.Join(errs...))
+}
+
+// pickUnseen draws backends by weight, skipping those without quotes for
+// the filter, and picks a quote seen rejects as new from the first one that
+// has any. ErrPoolExhausted is only returned once every backend's matches
+// have been seen.
+func (w *WeightedSource) pickUnseen(filter Filter, seen func(*Quote) bool) (*Quote, error) {
+	exhausted := false
+	for _, entry := range w.order() {
+		if len(Matches(entry.source, filter)) == 0 {
+			continue
+		}
+		quote, err := PickUnseen(entry.source, filter, seen)
+		if err == nil {
+			return quote, nil
+		}
+		if errors.Is(err, ErrPoolExhausted) {
+			exhausted = true
+		}
+	}
+	if exhausted {
+		return nil, ErrPoolExhausted
+	}
+	return Pick(w, filter), nil
+}
+
+// order returns the entries in the order of a weighted draw without
+// replacement, so a failing backend hands over to the next most likely one
+func (w *WeightedSource) order() []weightedEntry {
+	remaining := make([]weightedEntry, len(w.entries))
+	copy(remaining, w.entries)
+	total := w.total
+
+	ordered := make([]weightedEntry, 0, len(w.entries))
+	for len(remaining) > 0 {
+		target := rand.Float64() * total
+		index := len(remaining) - 1
+		for i, entry := range remaining {
+			if target < entry.weight {
+				index = i
+				break
+			}
+			target -= entry.weight
+		}
+
+		ordered = append(ordered, remaining[index])
+		total -= remaining[index].weight
+		remaining = append(remaining[:index], remaining[index+1:]...)
+	}
+	return ordered
+}
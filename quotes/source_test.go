@@ -0,0 +1,250 @@
+package quotes
+
+import (
+	"errors"
+	"testing"
+)
+
+// failingSource is a source whose every draw fails
+type failingSource struct{}
+
+func (failingSource) Random(Filter) (*Quote, error) {
+	return nil, errors.New("source is down")
+}
+
+func TestSequenceSource(t *testing.T) {
+	quotes := []Quote{
+		{Content: "First.", Tags: []string{"a"}},
+		{Content: "Second.", Tags: []string{"b"}},
+		{Content: "Third.", Tags: []string{"a"}},
+	}
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		draws   int
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "serves quotes in order and wraps around",
+			draws: 4,
+			want:  []string{"First.", "Second.", "Third.", "First."},
+		},
+		{
+			name:   "skips quotes the filter rejects",
+			filter: Filter{Tags: []string{"a"}},
+			draws:  3,
+			want:   []string{"First.", "Third.", "First."},
+		},
+		{
+			name:    "fails when nothing matches",
+			filter:  Filter{Tags: []string{"c"}},
+			draws:   1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewSequenceSource(quotes...)
+			var got []string
+			for i := 0; i < tt.draws; i++ {
+				quote, err := source.Random(tt.filter)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("Random() = %q, want an error", quote.Content)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Random() error: %v", err)
+				}
+				got = append(got, quote.Content)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("draw %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWeightedSource(t *testing.T) {
+	library := NewLibrary([]Quote{{Content: "From the library."}})
+
+	tests := []struct {
+		name    string
+		build   func() *WeightedSource
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "fails without backends",
+			build:   NewWeightedSource,
+			wantErr: true,
+		},
+		{
+			name: "ignores non-positive weights",
+			build: func() *WeightedSource {
+				return NewWeightedSource().Add(library, 0).Add(library, -1)
+			},
+			wantErr: true,
+		},
+		{
+			name: "falls through a failing backend",
+			build: func() *WeightedSource {
+				return NewWeightedSource().Add(failingSource{}, 100).Add(library, 0.01)
+			},
+			want: "From the library.",
+		},
+		{
+			name: "fails when every backend fails",
+			build: func() *WeightedSource {
+				return NewWeightedSource().Add(failingSource{}, 1).Add(failingSource{}, 1)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := tt.build().Random(Filter{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Random() = %q, want an error", quote.Content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Random() error: %v", err)
+			}
+			if quote.Content != tt.want {
+				t.Errorf("Random() = %q, want %q", quote.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestPick(t *testing.T) {
+	library := NewLibrary([]Quote{{Content: "Tagged.", Tags: []string{"science"}}})
+
+	tests := []struct {
+		name       string
+		source     Source
+		filter     Filter
+		want       string
+		wantSource string
+	}{
+		{
+			name:   "returns a matching quote",
+			source: library,
+			filter: Filter{Tags: []string{"science"}},
+			want:   "Tagged.",
+		},
+		{
+			name:   "relaxes a filter nothing matches",
+			source: library,
+			filter: Filter{Tags: []string{"poetry"}},
+			want:   "Tagged.",
+		},
+		{
+			name:       "falls back to the embedded library",
+			source:     failingSource{},
+			wantSource: "embedded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := Pick(tt.source, tt.filter)
+			if quote == nil {
+				t.Fatal("Pick() = nil")
+			}
+			if tt.want != "" && quote.Content != tt.want {
+				t.Errorf("Pick() = %q, want %q", quote.Content, tt.want)
+			}
+			if tt.wantSource != "" && quote.Source != tt.wantSource {
+				t.Errorf("Pick() source = %q, want %q", quote.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestPickUnseen(t *testing.T) {
+	quotes := []Quote{{Content: "Old."}, {Content: "New."}}
+
+	tests := []struct {
+		name    string
+		source  Source
+		seen    []string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "picks the quote not yet seen",
+			source: NewLibrary(quotes),
+			seen:   []string{"Old."},
+			want:   "New.",
+		},
+		{
+			name:    "reports an exhausted library",
+			source:  NewLibrary(quotes),
+			seen:    []string{"Old.", "New."},
+			wantErr: ErrPoolExhausted,
+		},
+		{
+			name: "searches every backend of a listable mix",
+			source: NewWeightedSource().
+				Add(NewLibrary(quotes[:1]), 100).
+				Add(NewLibrary(quotes[1:]), 0.01),
+			seen: []string{"Old."},
+			want: "New.",
+		},
+		{
+			name: "reports an exhausted listable mix",
+			source: NewWeightedSource().
+				Add(NewLibrary(quotes[:1]), 1).
+				Add(NewLibrary(quotes[1:]), 1),
+			seen:    []string{"Old.", "New."},
+			wantErr: ErrPoolExhausted,
+		},
+		{
+			name:   "accepts a repeat from a source it cannot list",
+			source: NewSequenceSource(quotes[0]),
+			seen:   []string{"Old."},
+			want:   "Old.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := func(q *Quote) bool {
+				for _, content := range tt.seen {
+					if q.Content == content {
+						return true
+					}
+				}
+				return false
+			}
+
+			quote, err := PickUnseen(tt.source, Filter{}, seen)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("PickUnseen() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PickUnseen() error: %v", err)
+			}
+			if quote.Content != tt.want {
+				t.Errorf("PickUnseen() = %q, want %q", quote.Content, tt.want)
+			}
+		})
+	}
+}
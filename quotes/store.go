@@ -0,0 +1,298 @@
+package quotes
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalSource names quotes added to the local library
+const LocalSource = "library"
+
+// LibraryStore is the editable quote library kept in a JSON file. Until the
+// file exists it holds a copy of the embedded library, so every change
+// starts from the quotes already in rotation.
+type LibraryStore struct {
+	path   string
+	quotes []Quote
+}
+
+// DefaultLibraryPath returns the local library file in the user's config
+// directory
+func DefaultLibraryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "typeracer-tui", "library.json"), nil
+}
+
+// OpenLibraryStore loads the library at path, starting from the embedded
+// quotes when the file does not exist yet
+func OpenLibraryStore(path string) (*LibraryStore, error) {
+	store := &LibraryStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		store.quotes = DefaultLibrary().All()
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.quotes); err != nil {
+		return nil, fmt.Errorf("failed to parse library: %w", err)
+	}
+	store.quotes = NewLibrary(store.quotes).All()
+	return store, nil
+}
+
+// LocalLibrary returns the local library if one has been saved, otherwise
+// the embedded library
+func LocalLibrary() (*Library, error) {
+	path, err := DefaultLibraryPath()
+	if err != nil {
+		return DefaultLibrary(), nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return DefaultLibrary(), nil
+	}
+
+	store, err := OpenLibraryStore(path)
+	if err != nil {
+		return nil, err
+	}
+	library := store.Library()
+	if library.Len() == 0 {
+		return nil, fmt.Errorf("the local library at %s is empty", path)
+	}
+	return library, nil
+}
+
+// Path returns the file the library is saved to
+func (s *LibraryStore) Path() string {
+	return s.path
+}
+
+// Library returns the stored quotes as a library
+func (s *LibraryStore) Library() *Library {
+	return NewLibrary(s.quotes)
+}
+
+// Search returns the quotes whose text, author, tags or ID contain every
+// word of the query, ignoring case
+func (s *LibraryStore) Search(query string) []Quote {
+	words := strings.Fields(strings.ToLower(query))
+
+	var matches []Quote
+	for _, quote := range s.quotes {
+		haystack := strings.ToLower(strings.Join(append([]string{quote.ID, quote.Content, quote.Author}, quote.Tags...), " "))
+		found := true
+		for _, word := range words {
+			if !strings.Contains(haystack, word) {
+				found = false
+				break
+			}
+		}
+		if found {
+			matches = append(matches, quote)
+		}
+	}
+	return matches
+}
+
+// Add stores a new quote, refusing empty text and passages already in the
+// library
+func (s *LibraryStore) Add(quote Quote) (Quote, error) {
+	quote.Content = strings.TrimSpace(quote.Content)
+	quote.Author = strings.TrimSpace(quote.Author)
+	if quote.Content == "" {
+		return Quote{}, fmt.Errorf("quote text is empty")
+	}
+	if quote.Author == "" {
+		quote.Author = "Unknown"
+	}
+	quote.Tags = cleanTags(quote.Tags)
+	quote.ID = ""
+	Identify(&quote, LocalSource)
+
+	for _, existing := range s.quotes {
+		if Fingerprint(&existing) == Fingerprint(&quote) {
+			return Quote{}, fmt.Errorf("quote is already in the library as %s", existing.ID)
+		}
+	}
+	s.quotes = append(s.quotes, quote)
+	return quote, nil
+}
+
+// Import adds every new quote, returning how many were added and how many
+// were skipped as empty or already present
+func (s *LibraryStore) Import(quotes []Quote) (added, skipped int) {
+	for _, quote := range quotes {
+		if _, err := s.Add(quote); err != nil {
+			skipped++
+			continue
+		}
+		added++
+	}
+	return added, skipped
+}
+
+// Delete removes the quotes with the given IDs and returns how many were
+// removed, failing without changes if any ID is unknown
+func (s *LibraryStore) Delete(ids ...string) (int, error) {
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = false
+	}
+
+	kept := s.quotes[:0:0]
+	for _, quote := range s.quotes {
+		if _, found := remove[quote.ID]; found {
+			remove[quote.ID] = true
+			continue
+		}
+		kept = append(kept, quote)
+	}
+
+	for id, found := range remove {
+		if !found {
+			return 0, fmt.Errorf("no quote with ID %s", id)
+		}
+	}
+	deleted := len(s.quotes) - len(kept)
+	s.quotes = kept
+	return deleted, nil
+}
+
+// Dedupe removes quotes whose text matches an earlier one, ignoring case,
+// punctuation and spacing, and returns the removed quotes
+func (s *LibraryStore) Dedupe() []Quote {
+	seen := make(map[string]bool)
+	kept := s.quotes[:0:0]
+
+	var removed []Quote
+	for _, quote := range s.quotes {
+		fingerprint := Fingerprint(&quote)
+		if seen[fingerprint] {
+			removed = append(removed, quote)
+			continue
+		}
+		seen[fingerprint] = true
+		kept = append(kept, quote)
+	}
+	s.quotes = kept
+	return removed
+}
+
+// Save writes the library to its file
+func (s *LibraryStore) Save() error {
+	if err := WriteJSONFile(s.path, s.quotes); err != nil {
+		return fmt.Errorf("failed to write library: %w", err)
+	}
+	return nil
+}
+
+// ReadQuotesFile reads quotes from a .json file holding an array of quotes
+// or a .csv file with content, author and tags columns
+func ReadQuotesFile(path string) ([]Quote, error) {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		defer file.Close()
+
+		quotes, err := ReadQuotesCSV(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return quotes, nil
+	}
+	return loadJSONFile(path)
+}
+
+// ReadQuotesCSV reads quotes from CSV with a header row naming a content
+// column and optional author and tags columns. Tags are separated by ';'
+// or '|'.
+func ReadQuotesCSV(r io.Reader) ([]Quote, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["content"]; !ok {
+		return nil, fmt.Errorf("missing content column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var quotes []Quote
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		language, err := ParseLanguage(field(record, "language"))
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, Quote{
+			Content: field(record, "content"),
+			Author:  field(record, "author"),
+			Tags: strings.FieldsFunc(field(record, "tags"), func(r rune) bool {
+				return r == ';' || r == '|'
+			}),
+			Language: language,
+		})
+	}
+	return quotes, nil
+}
+
+// WriteQuotesCSV writes quotes as CSV with id, content, author, tags and
+// language columns, the tags separated by ';'
+func WriteQuotesCSV(w io.Writer, quotes []Quote) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "content", "author", "tags", "language"}); err != nil {
+		return err
+	}
+	for _, quote := range quotes {
+		record := []string{quote.ID, quote.Content, quote.Author, strings.Join(quote.Tags, ";"), string(LanguageOf(&quote))}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteQuotesJSON writes quotes as an indented JSON array that LoadDir and
+// ReadQuotesFile can read back
+func WriteQuotesJSON(w io.Writer, quotes []Quote) error {
+	data, err := json.MarshalIndent(quotes, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,53 @@
+package quotes
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLibraryStoreDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		ids         func(first, second string) []string
+		wantDeleted int
+		wantErr     bool
+	}{
+		{
+			name:        "each ID once",
+			ids:         func(first, second string) []string { return []string{first, second} },
+			wantDeleted: 2,
+		},
+		{
+			name:        "an ID named twice",
+			ids:         func(first, _ string) []string { return []string{first, first} },
+			wantDeleted: 1,
+		},
+		{
+			name:    "an unknown ID",
+			ids:     func(first, _ string) []string { return []string{first, "missing"} },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := OpenLibraryStore(filepath.Join(t.TempDir(), "library.json"))
+			if err != nil {
+				t.Fatalf("OpenLibraryStore() error: %v", err)
+			}
+			all := store.Library().All()
+			before := len(all)
+
+			deleted, err := store.Delete(tt.ids(all[0].ID, all[1].ID)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("Delete() = %d, want %d", deleted, tt.wantDeleted)
+			}
+			if after := store.Library().Len(); after != before-tt.wantDeleted {
+				t.Errorf("library holds %d quotes, want %d", after, before-tt.wantDeleted)
+			}
+		})
+	}
+}
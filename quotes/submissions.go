@@ -0,0 +1,335 @@
+package quotes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Submission statuses
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Limits on submitted quote text
+const (
+	minSubmissionLength = 20
+	maxSubmissionLength = 600
+)
+
+// Submission is a quote proposed by a player, waiting for or past review
+type Submission struct {
+	ID          string    `json:"id"`
+	Content     string    `json:"content"`
+	Author      string    `json:"author"`
+	Tags        []string  `json:"tags,omitempty"`
+	Language    Language  `json:"language,omitempty"`
+	SubmittedBy string    `json:"submitted_by"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	Status      string    `json:"status"`
+	ReviewedBy  string    `json:"reviewed_by,omitempty"`
+	ReviewedAt  time.Time `json:"reviewed_at,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// Quote returns the submission as a quote
+func (s *Submission) Quote() Quote {
+	quote := Quote{
+		Content:  s.Content,
+		Author:   s.Author,
+		Tags:     append([]string(nil), s.Tags...),
+		Language: s.Language,
+	}
+	Identify(&quote, "community")
+	return quote
+}
+
+// SubmissionQueue stores player submissions in a JSON file. Approved
+// submissions are served as a quote source.
+type SubmissionQueue struct {
+	path        string
+	submissions map[string]*Submission
+	approved    *Library
+	// library holds the quotes already served, which are not taken again
+	library Source
+	mu      sync.RWMutex
+}
+
+// DefaultSubmissionsPath returns the submissions file in the user's config
+// directory
+func DefaultSubmissionsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "typeracer-tui", "submissions.json"), nil
+}
+
+// NewSubmissionQueue creates a queue kept only in memory
+func NewSubmissionQueue() *SubmissionQueue {
+	queue := &SubmissionQueue{submissions: make(map[string]*Submission)}
+	queue.rebuild()
+	return queue
+}
+
+// OpenSubmissionQueue loads a queue from path; a missing file is empty
+func OpenSubmissionQueue(path string) (*SubmissionQueue, error) {
+	queue := NewSubmissionQueue()
+	queue.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return queue, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read submissions: %w", err)
+	}
+	if err := json.Unmarshal(data, &queue.submissions); err != nil {
+		return nil, fmt.Errorf("failed to parse submissions: %w", err)
+	}
+	queue.rebuild()
+	return queue, nil
+}
+
+// SetLibrary sets the quotes already served, so submissions repeating one
+// of them are turned away; sources that cannot list their quotes are not
+// checked
+func (q *SubmissionQueue) SetLibrary(library Source) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.library = library
+}
+
+// Submit adds a quote in the given language to the pending queue after
+// checking it is usable
+func (q *SubmissionQueue) Submit(content, author string, tags []string, language Language, submittedBy string) (Submission, error) {
+	content, author, err := validateSubmission(content, author)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.checkDuplicate(content, ""); err != nil {
+		return Submission{}, err
+	}
+
+	submission := &Submission{
+		ID:          uuid.New().String()[:8],
+		Content:     content,
+		Author:      author,
+		Tags:        cleanTags(tags),
+		Language:    language,
+		SubmittedBy: submittedBy,
+		SubmittedAt: time.Now(),
+		Status:      StatusPending,
+	}
+	q.submissions[submission.ID] = submission
+	if err := q.save(); err != nil {
+		delete(q.submissions, submission.ID)
+		return Submission{}, err
+	}
+	return *submission, nil
+}
+
+// Pending returns submissions awaiting review, oldest first
+func (q *SubmissionQueue) Pending() []Submission {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	var pending []Submission
+	for _, submission := range q.submissions {
+		if submission.Status == StatusPending {
+			pending = append(pending, *submission)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].SubmittedAt.Before(pending[j].SubmittedAt)
+	})
+	return pending
+}
+
+// Edit changes a pending submission's text, author and tags
+func (q *SubmissionQueue) Edit(id, content, author string, tags []string) error {
+	content, author, err := validateSubmission(content, author)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	submission, err := q.pending(id)
+	if err != nil {
+		return err
+	}
+	if err := q.checkDuplicate(content, id); err != nil {
+		return err
+	}
+
+	previous := *submission
+	submission.Content = content
+	submission.Author = author
+	submission.Tags = cleanTags(tags)
+	if err := q.save(); err != nil {
+		*submission = previous
+		return err
+	}
+	return nil
+}
+
+// Approve adds a pending submission to the quote pool
+func (q *SubmissionQueue) Approve(id, reviewer string) error {
+	return q.review(id, reviewer, StatusApproved, "")
+}
+
+// Reject removes a pending submission from review, noting why
+func (q *SubmissionQueue) Reject(id, reviewer, reason string) error {
+	return q.review(id, reviewer, StatusRejected, reason)
+}
+
+// review records a moderation decision
+func (q *SubmissionQueue) review(id, reviewer, status, reason string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	submission, err := q.pending(id)
+	if err != nil {
+		return err
+	}
+	previous := *submission
+	submission.Status = status
+	submission.ReviewedBy = reviewer
+	submission.ReviewedAt = time.Now()
+	submission.Reason = reason
+
+	if err := q.save(); err != nil {
+		*submission = previous
+		return err
+	}
+	q.rebuild()
+	return nil
+}
+
+// Select returns every approved quote matching the filter
+func (q *SubmissionQueue) Select(filter Filter) []Quote {
+	q.mu.RLock()
+	approved := q.approved
+	q.mu.RUnlock()
+
+	return approved.Select(filter)
+}
+
+// Random returns a random approved quote matching the filter
+func (q *SubmissionQueue) Random(filter Filter) (*Quote, error) {
+	matches := q.Select(filter)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no approved community quotes match the requested filter")
+	}
+	quote := matches[rand.Intn(len(matches))]
+	return &quote, nil
+}
+
+// Approved returns every approved quote
+func (q *SubmissionQueue) Approved() []Quote {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.approved.All()
+}
+
+// pending looks up a submission that can still be reviewed; the caller
+// holds the lock
+func (q *SubmissionQueue) pending(id string) (*Submission, error) {
+	submission, exists := q.submissions[id]
+	if !exists {
+		return nil, fmt.Errorf("submission %s not found", id)
+	}
+	if submission.Status != StatusPending {
+		return nil, fmt.Errorf("submission %s was already %s", id, submission.Status)
+	}
+	return submission, nil
+}
+
+// checkDuplicate turns away text that repeats a submission other than
+// except, unless that was rejected, or a quote in the library; the caller
+// holds the lock
+func (q *SubmissionQueue) checkDuplicate(content, except string) error {
+	fingerprint := Fingerprint(&Quote{Content: content})
+	for id, existing := range q.submissions {
+		if id != except && existing.Status != StatusRejected && Fingerprint(&Quote{Content: existing.Content}) == fingerprint {
+			return fmt.Errorf("this quote has already been submitted")
+		}
+	}
+	if q.library != nil {
+		for _, quote := range Matches(q.library, Filter{}) {
+			if Fingerprint(&quote) == fingerprint {
+				return fmt.Errorf("this quote is already in the library")
+			}
+		}
+	}
+	return nil
+}
+
+// rebuild refreshes the library of approved quotes; the caller holds the
+// lock
+func (q *SubmissionQueue) rebuild() {
+	var approved []Quote
+	for _, submission := range q.submissions {
+		if submission.Status == StatusApproved {
+			approved = append(approved, submission.Quote())
+		}
+	}
+	q.approved = NewLibrary(approved)
+}
+
+// save writes the submissions file, if there is one
+func (q *SubmissionQueue) save() error {
+	if q.path == "" {
+		return nil
+	}
+	if err := WriteJSONFile(q.path, q.submissions); err != nil {
+		return fmt.Errorf("failed to write submissions: %w", err)
+	}
+	return nil
+}
+
+// validateSubmission tidies submitted text and checks it can be raced on
+func validateSubmission(content, author string) (string, string, error) {
+	content = DefaultNormalizer().String(content)
+	author = strings.Join(strings.Fields(author), " ")
+
+	switch length := GraphemeCount(content); {
+	case length < minSubmissionLength:
+		return "", "", fmt.Errorf("quote is too short (at least %d characters)", minSubmissionLength)
+	case length > maxSubmissionLength:
+		return "", "", fmt.Errorf("quote is too long (at most %d characters)", maxSubmissionLength)
+	}
+	if author == "" {
+		author = "Unknown"
+	}
+	return content, author, nil
+}
+
+// cleanTags trims, lower-cases and drops empty tags
+func cleanTags(tags []string) []string {
+	var cleaned []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return cleaned
+}
@@ -0,0 +1,95 @@
+package quotes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const submittedText = "A quote long enough to be submitted for review."
+
+func TestSubmissionQueueSubmit(t *testing.T) {
+	tests := []struct {
+		name    string
+		library []Quote
+		earlier []string
+		content string
+		wantErr bool
+	}{
+		{name: "a new quote", content: submittedText},
+		{name: "too short", content: "Too short.", wantErr: true},
+		{name: "already submitted", earlier: []string{submittedText}, content: "a QUOTE long enough to be submitted for review", wantErr: true},
+		{name: "already in the library", library: []Quote{{Content: submittedText}}, content: submittedText, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewSubmissionQueue()
+			queue.SetLibrary(NewLibrary(tt.library))
+			for _, content := range tt.earlier {
+				if _, err := queue.Submit(content, "", nil, English, "player"); err != nil {
+					t.Fatalf("Submit(%q) error: %v", content, err)
+				}
+			}
+
+			_, err := queue.Submit(tt.content, "Author", nil, English, "player")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Submit(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubmissionQueueEdit(t *testing.T) {
+	other := "Another quote that is waiting to be reviewed."
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "new text", content: "A reworded quote that is still long enough."},
+		{name: "its own text", content: submittedText},
+		{name: "too short", content: "Too short.", wantErr: true},
+		{name: "another submission", content: other, wantErr: true},
+		{name: "a library quote", content: "A quote the library already serves to players.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewSubmissionQueue()
+			queue.SetLibrary(NewLibrary([]Quote{{Content: "A quote the library already serves to players."}}))
+			submission, err := queue.Submit(submittedText, "", nil, English, "player")
+			if err != nil {
+				t.Fatalf("Submit() error: %v", err)
+			}
+			if _, err := queue.Submit(other, "", nil, English, "player"); err != nil {
+				t.Fatalf("Submit() error: %v", err)
+			}
+
+			err = queue.Edit(submission.ID, tt.content, "Author", nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Edit(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubmissionQueueSubmitFailedSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "config")
+	queue, err := OpenSubmissionQueue(filepath.Join(dir, "submissions.json"))
+	if err != nil {
+		t.Fatalf("OpenSubmissionQueue() error: %v", err)
+	}
+	// A file where the queue's directory should be makes every save fail
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := queue.Submit(submittedText, "", nil, English, "player"); err == nil {
+		t.Fatal("Submit() succeeded without saving")
+	}
+	if pending := queue.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %d submissions, want the failed one rolled back", len(pending))
+	}
+}
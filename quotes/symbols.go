@@ -0,0 +1,143 @@
+package quotes
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultSymbolDensity is the share of drill tokens that exercise digits
+// and symbols when no density is given
+const DefaultSymbolDensity = 0.5
+
+// SymbolOptions controls how number and symbol drills are generated
+type SymbolOptions struct {
+	// Density is the share of tokens, from 0 to 1, that are numbers,
+	// brackets, operators or shifted symbols; the rest are common words
+	Density float64
+}
+
+// ParseSymbolDensity parses a density given as a percentage such as "30"
+// or "30%"
+func ParseSymbolDensity(s string) (float64, error) {
+	if s == "" {
+		return DefaultSymbolDensity, nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+	if err != nil || percent < 1 || percent > 100 {
+		return 0, fmt.Errorf("invalid symbol density %q (want a percentage from 1 to 100)", s)
+	}
+	return float64(percent) / 100, nil
+}
+
+// symbolDrill builds one drill token around a common word
+type symbolDrill func(rng *rand.Rand, word func() string) string
+
+// symbolDrills cover digits, brackets, operators and shifted symbols in
+// roughly equal measure
+var symbolDrills = []symbolDrill{
+	// Digits
+	func(rng *rand.Rand, word func() string) string { return strconv.Itoa(rng.Intn(10000)) },
+	func(rng *rand.Rand, word func() string) string {
+		return fmt.Sprintf("%d.%02d", rng.Intn(1000), rng.Intn(100))
+	},
+	func(rng *rand.Rand, word func() string) string {
+		return fmt.Sprintf("%03d-%04d", rng.Intn(1000), rng.Intn(10000))
+	},
+	func(rng *rand.Rand, word func() string) string {
+		return fmt.Sprintf("v%d.%d.%d", rng.Intn(10), rng.Intn(30), rng.Intn(100))
+	},
+
+	// Brackets
+	func(rng *rand.Rand, word func() string) string { return "(" + word() + ")" },
+	func(rng *rand.Rand, word func() string) string {
+		return word() + "[" + strconv.Itoa(rng.Intn(10)) + "]"
+	},
+	func(rng *rand.Rand, word func() string) string { return "{" + word() + "}" },
+	func(rng *rand.Rand, word func() string) string { return word() + "(" + word() + ", " + word() + ")" },
+	func(rng *rand.Rand, word func() string) string { return "<" + word() + ">" },
+
+	// Operators
+	func(rng *rand.Rand, word func() string) string {
+		operators := []string{"+", "-", "*", "/", "=", "==", "!=", "<=", ">=", "+=", "&&", "||", "->", "=>", "%"}
+		return word() + " " + operators[rng.Intn(len(operators))] + " " + strconv.Itoa(rng.Intn(100))
+	},
+	func(rng *rand.Rand, word func() string) string { return word() + "++" },
+	func(rng *rand.Rand, word func() string) string { return "!" + word() },
+
+	// Shifted symbols
+	func(rng *rand.Rand, word func() string) string { return "@" + word() },
+	func(rng *rand.Rand, word func() string) string { return "#" + word() },
+	func(rng *rand.Rand, word func() string) string { return "$" + strconv.Itoa(rng.Intn(1000)) },
+	func(rng *rand.Rand, word func() string) string { return strconv.Itoa(rng.Intn(101)) + "%" },
+	func(rng *rand.Rand, word func() string) string { return word() + "_" + word() },
+	func(rng *rand.Rand, word func() string) string { return word() + "@" + word() + ".com" },
+	func(rng *rand.Rand, word func() string) string { return "~/" + word() + "/" + word() },
+	func(rng *rand.Rand, word func() string) string { return word() + " & " + word() },
+	func(rng *rand.Rand, word func() string) string { return "*" + word() },
+	func(rng *rand.Rand, word func() string) string { return "\"" + word() + "\"" },
+	func(rng *rand.Rand, word func() string) string { return word() + "^" + strconv.Itoa(rng.Intn(10)) },
+	func(rng *rand.Rand, word func() string) string { return word() + " | " + word() },
+	func(rng *rand.Rand, word func() string) string {
+		return word() + ":" + strconv.Itoa(rng.Intn(100)) + ";"
+	},
+}
+
+// SymbolSource generates drills heavy in digits, brackets, operators and
+// shifted symbols, mixed with common words
+type SymbolSource struct {
+	words   []string
+	options SymbolOptions
+	mu      sync.Mutex
+	rng     *rand.Rand
+}
+
+// NewSymbolSource creates a drill generator; a density outside (0, 1] uses
+// the default
+func NewSymbolSource(options SymbolOptions) *SymbolSource {
+	if options.Density <= 0 || options.Density > 1 {
+		options.Density = DefaultSymbolDensity
+	}
+	return &SymbolSource{
+		words:   EnglishWords(Words200),
+		options: options,
+		rng:     rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// Random generates a drill sized for the filter's length bucket. Tags and
+// difficulty do not apply to generated text.
+func (s *SymbolSource) Random(filter Filter) (*Quote, error) {
+	s.mu.Lock()
+	content := s.generate(wordTargetLengths[filter.Length])
+	s.mu.Unlock()
+
+	quote := &Quote{
+		Content: content,
+		Author:  fmt.Sprintf("Numbers and symbols drill (%.0f%%)", s.options.Density*100),
+		Tags:    []string{"symbols"},
+	}
+	Identify(quote, GeneratedSource)
+	return quote, nil
+}
+
+// generate builds a drill of roughly target characters
+func (s *SymbolSource) generate(target int) string {
+	word := func() string {
+		return s.words[s.rng.Intn(len(s.words))]
+	}
+
+	var tokens []string
+	length := 0
+	for length < target {
+		token := word()
+		if s.rng.Float64() < s.options.Density {
+			token = symbolDrills[s.rng.Intn(len(symbolDrills))](s.rng, word)
+		}
+		tokens = append(tokens, token)
+		length += len(token) + 1
+	}
+	return strings.Join(tokens, " ")
+}
@@ -0,0 +1,105 @@
+package quotes
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SequentialSource steps through a document passage by passage instead of
+// picking at random. Callers report each typed passage with Advance.
+type SequentialSource interface {
+	Source
+	// Advance moves on to the next passage and remembers the position
+	Advance() error
+	// Progress reports how far through the document the reader is
+	Progress() DocumentProgress
+}
+
+// PassageLength returns the largest passage, in characters, a document is
+// split into for a length bucket
+func PassageLength(length LengthBucket) int {
+	switch length {
+	case LengthShort:
+		return shortMaxLength
+	case LengthMedium:
+		return mediumMaxLength
+	case LengthLong:
+		return maxSnippetLength
+	default:
+		return 250
+	}
+}
+
+// SplitPassages breaks a document into passages of at most maxLength
+// characters, keeping sentences whole where they fit and never joining text
+// across paragraphs
+func SplitPassages(text string, maxLength int) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var passages []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.Join(strings.Fields(paragraph), " ")
+		if paragraph == "" {
+			continue
+		}
+
+		current := ""
+		for _, piece := range splitSentences(paragraph, maxLength) {
+			if current != "" && len([]rune(current))+1+len([]rune(piece)) > maxLength {
+				passages = append(passages, current)
+				current = ""
+			}
+			if current == "" {
+				current = piece
+			} else {
+				current += " " + piece
+			}
+		}
+		if current != "" {
+			passages = append(passages, current)
+		}
+	}
+	return passages
+}
+
+// splitSentences splits a paragraph after sentence-ending punctuation and
+// breaks sentences longer than maxLength between words
+func splitSentences(paragraph string, maxLength int) []string {
+	var sentences []string
+	start := 0
+	runes := []rune(paragraph)
+	for i, r := range runes {
+		if (r == '.' || r == '?' || r == '!') && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+			sentences = append(sentences, strings.TrimSpace(string(runes[start:i+1])))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(string(runes[start:])); rest != "" {
+		sentences = append(sentences, rest)
+	}
+
+	var pieces []string
+	for _, sentence := range sentences {
+		if len([]rune(sentence)) <= maxLength {
+			pieces = append(pieces, sentence)
+			continue
+		}
+
+		current := ""
+		for _, word := range strings.Fields(sentence) {
+			if current != "" && len([]rune(current))+1+len([]rune(word)) > maxLength {
+				pieces = append(pieces, current)
+				current = ""
+			}
+			if current == "" {
+				current = word
+			} else {
+				current += " " + word
+			}
+		}
+		if current != "" {
+			pieces = append(pieces, current)
+		}
+	}
+	return pieces
+}
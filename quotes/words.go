@@ -0,0 +1,192 @@
+package quotes
+
+import (
+	_ "embed"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed data/words/english.txt
+var embeddedEnglishWords string
+
+// Dictionary sizes for generated word sequences
+const (
+	Words200 = 200
+	Words1k  = 1000
+	Words5k  = 5000
+	Words10k = 10000
+)
+
+var (
+	englishWords     []string
+	englishWordsOnce sync.Once
+)
+
+// EnglishWords returns up to n of the most frequent English words, most
+// common first
+func EnglishWords(n int) []string {
+	englishWordsOnce.Do(func() {
+		englishWords = parseWordList(embeddedEnglishWords)
+	})
+	if n <= 0 || n > len(englishWords) {
+		n = len(englishWords)
+	}
+	return englishWords[:n]
+}
+
+// parseWordList returns the words of a list with one word per line,
+// skipping blank lines and # comments
+func parseWordList(data string) []string {
+	var words []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words
+}
+
+// ParseWordListSize parses a dictionary size such as "200", "1k" or "10k"
+func ParseWordListSize(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "", "1k", "1000":
+		return Words1k, nil
+	case "200":
+		return Words200, nil
+	case "5k", "5000":
+		return Words5k, nil
+	case "10k", "10000":
+		return Words10k, nil
+	}
+	return 0, fmt.Errorf("unknown word list %q (want 200, 1k, 5k or 10k)", s)
+}
+
+// WordOptions controls how random word sequences are generated
+type WordOptions struct {
+	// Size is how many of the most frequent words to draw from
+	Size int
+	// Punctuation capitalizes sentences and sprinkles in commas, full stops
+	// and other punctuation
+	Punctuation bool
+	// Numbers mixes in numbers between words
+	Numbers bool
+}
+
+// Target lengths of generated text for each length bucket
+var wordTargetLengths = map[LengthBucket]int{
+	LengthShort:  60,
+	LengthMedium: 150,
+	LengthLong:   300,
+	LengthAny:    150,
+}
+
+// Punctuation appended to words, roughly weighted by how often it occurs
+var wordPunctuation = []string{",", ",", ",", ".", ".", ".", "?", "!", ";", ":"}
+
+// GeneratedSource is the source name of generated text, which is different
+// every time and so is not tracked per quote
+const GeneratedSource = "generated"
+
+// WordSource generates random sequences of common words instead of serving
+// fixed quotes
+type WordSource struct {
+	words   []string
+	options WordOptions
+	mu      sync.Mutex
+	rng     *rand.Rand
+}
+
+// NewWordSource creates a generator drawing from the options' dictionary,
+// defaulting to the top 1k words
+func NewWordSource(options WordOptions) *WordSource {
+	if options.Size <= 0 {
+		options.Size = Words1k
+	}
+	return &WordSource{
+		words:   EnglishWords(options.Size),
+		options: options,
+		rng:     rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// Random generates a sequence of words sized for the filter's length
+// bucket. Tags and difficulty do not apply to generated text.
+func (s *WordSource) Random(filter Filter) (*Quote, error) {
+	if len(s.words) == 0 {
+		return nil, fmt.Errorf("word list is empty")
+	}
+
+	s.mu.Lock()
+	content := s.generate(wordTargetLengths[filter.Length])
+	s.mu.Unlock()
+
+	quote := &Quote{
+		Content: content,
+		Author:  fmt.Sprintf("Top %s English words", formatWordListSize(s.options.Size)),
+		Tags:    []string{"words"},
+	}
+	Identify(quote, GeneratedSource)
+	return quote, nil
+}
+
+// generate builds a sequence of roughly target characters
+func (s *WordSource) generate(target int) string {
+	var words []string
+	length := 0
+	sentenceStart := true
+
+	for length < target {
+		word := s.words[s.rng.Intn(len(s.words))]
+		if s.options.Numbers && s.rng.Intn(8) == 0 {
+			word = strconv.Itoa(s.rng.Intn(10000))
+		}
+
+		if s.options.Punctuation {
+			if sentenceStart {
+				word = capitalize(word)
+			}
+			sentenceStart = false
+
+			switch roll := s.rng.Intn(20); {
+			case roll < 4:
+				mark := wordPunctuation[s.rng.Intn(len(wordPunctuation))]
+				word += mark
+				sentenceStart = mark == "." || mark == "?" || mark == "!"
+			case roll == 4:
+				word = "\"" + word + "\""
+			case roll == 5:
+				word = "(" + word + ")"
+			}
+		}
+
+		words = append(words, word)
+		length += len(word) + 1
+	}
+
+	// End on a full sentence
+	if last := words[len(words)-1]; s.options.Punctuation && !strings.ContainsAny(last[len(last)-1:], ".?!") {
+		words[len(words)-1] = strings.TrimRight(last, ",;:") + "."
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize upper-cases the first letter of a word
+func capitalize(word string) string {
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// formatWordListSize renders a dictionary size as "200", "1k" or "10k"
+func formatWordListSize(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%dk", n/1000)
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,57 @@
+package quotes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnglishWordsCoverEveryListSize(t *testing.T) {
+	words := EnglishWords(0)
+	if len(words) < Words10k {
+		t.Fatalf("embedded word list has %d words, want at least %d", len(words), Words10k)
+	}
+
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		if seen[word] {
+			t.Errorf("word %q is listed twice", word)
+		}
+		seen[word] = true
+	}
+}
+
+func TestParseWordList(t *testing.T) {
+	got := parseWordList("# source and license\n#\nthe\n\n  of \nand\n")
+	want := []string{"the", "of", "and"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("parseWordList() = %q, want %q", got, want)
+	}
+}
+
+func TestParseWordListSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{in: "", want: Words1k},
+		{in: "200", want: Words200},
+		{in: "1k", want: Words1k},
+		{in: "5K", want: Words5k},
+		{in: "10k", want: Words10k},
+		{in: "10000", want: Words10k},
+		{in: "20k", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseWordListSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWordListSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWordListSize(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
@@ -10,37 +11,90 @@ import (
 	"time"
 
 	"typeracer-tui/game"
+	"typeracer-tui/quotes"
 	"typeracer-tui/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 // SSHServer represents the SSH server for multiplayer games
 type SSHServer struct {
-	manager *game.Manager
-	port    string
+	manager   *game.Manager
+	port      string
+	adminKeys []ssh.PublicKey
 }
 
 // NewSSHServer creates a new SSH server
-func NewSSHServer(port string) *SSHServer {
+func NewSSHServer(port string, manager *game.Manager) *SSHServer {
 	return &SSHServer{
-		manager: game.NewManager(),
+		manager: manager,
 		port:    port,
 	}
 }
 
+// LoadAdminKeys reads an authorized_keys file listing the public keys of
+// players allowed to review quote submissions
+func (s *SSHServer) LoadAdminKeys(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read admin keys: %w", err)
+	}
+
+	for len(bytes.TrimSpace(data)) > 0 {
+		key, _, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse admin keys: %w", err)
+		}
+		s.adminKeys = append(s.adminKeys, key)
+		data = rest
+	}
+	return nil
+}
+
+// isAdmin reports whether a session authenticated with an admin key
+func (s *SSHServer) isAdmin(session ssh.Session) bool {
+	key := session.PublicKey()
+	if key == nil {
+		return false
+	}
+	for _, admin := range s.adminKeys {
+		if ssh.KeysEqual(key, admin) {
+			return true
+		}
+	}
+	return false
+}
+
+// voterKey returns the fingerprint of the key a session authenticated with,
+// which quote votes are kept under since usernames can be anything; sessions
+// without a key get none
+func voterKey(session ssh.Session) string {
+	key := session.PublicKey()
+	if key == nil {
+		return ""
+	}
+	return gossh.FingerprintSHA256(key)
+}
+
 // Start starts the SSH server
 func (s *SSHServer) Start() error {
-	// Create SSH server
-	server, err := wish.NewServer(
-		wish.WithAddress(":"+s.port),
+	options := []ssh.Option{
+		wish.WithAddress(":" + s.port),
 		wish.WithHostKeyPath(".ssh/host_key"),
 		wish.WithMiddleware(
 			s.gameMiddleware(),
 		),
-	)
+		// Anyone may join, but offering a key lets admins be recognised and
+		// lets players rate quotes
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
+	}
+
+	// Create SSH server
+	server, err := wish.NewServer(options...)
 	if err != nil {
 		return fmt.Errorf("failed to create SSH server: %w", err)
 	}
@@ -77,8 +131,15 @@ func (s *SSHServer) gameMiddleware() wish.Middleware {
 			playerID := session.User()
 			playerName := session.User() // Use username as display name
 
+			// Players pick their language as the SSH command, e.g. "ssh host de"
+			var language quotes.Language
+			var languageErr error
+			if args := session.Command(); len(args) > 0 {
+				language, languageErr = quotes.ParseLanguage(args[0])
+			}
+
 			// Add player to manager
-			_, err := s.manager.AddPlayer(playerID, playerName)
+			player, err := s.manager.AddPlayer(playerID, playerName, language)
 			if err != nil {
 				log.Printf("Failed to add player %s: %v", playerID, err)
 				session.Close()
@@ -86,9 +147,12 @@ func (s *SSHServer) gameMiddleware() wish.Middleware {
 			}
 
 			log.Printf("Player %s (%s) connected", playerName, playerID)
+			if languageErr != nil {
+				fmt.Fprintf(session, "%v; racing in %s\n", languageErr, player.Language.Label())
+			}
 
-			// Try to find an available lobby or create one
-			lobby := s.findOrCreateLobby()
+			// Try to find an available lobby in their language or create one
+			lobby := s.findOrCreateLobby(player.Language)
 			if lobby == nil {
 				log.Printf("Failed to create lobby for player %s", playerID)
 				session.Close()
@@ -104,6 +168,8 @@ func (s *SSHServer) gameMiddleware() wish.Middleware {
 
 			// Create Bubble Tea program
 			model := ui.NewLobbyModel(s.manager, playerID, playerName, lobby.ID, lobby.MaxPlayers)
+			model.SetModerator(s.isAdmin(session))
+			model.SetVoter(voterKey(session))
 			program := tea.NewProgram(model, tea.WithAltScreen())
 
 			// Handle lobby updates and game transitions
@@ -122,10 +188,11 @@ func (s *SSHServer) gameMiddleware() wish.Middleware {
 	}
 }
 
-// findOrCreateLobby finds an available lobby or creates a new one
-func (s *SSHServer) findOrCreateLobby() *game.Lobby {
+// findOrCreateLobby finds an available lobby racing in a language or
+// creates a new one
+func (s *SSHServer) findOrCreateLobby(language quotes.Language) *game.Lobby {
 	// Try to find an available lobby
-	availableLobbies := s.manager.GetAvailableLobbies()
+	availableLobbies := s.manager.GetAvailableLobbies(language)
 	for _, lobby := range availableLobbies {
 		if len(lobby.GetPlayers()) < lobby.MaxPlayers {
 			return lobby
@@ -133,7 +200,7 @@ func (s *SSHServer) findOrCreateLobby() *game.Lobby {
 	}
 
 	// Create new lobby with default settings
-	lobby, err := s.manager.CreateLobby(4) // Default to 4 players max
+	lobby, err := s.manager.CreateLobby(4, language) // Default to 4 players max
 	if err != nil {
 		log.Printf("Failed to create lobby: %v", err)
 		return nil
@@ -148,10 +215,15 @@ func (s *SSHServer) handlePlayerUpdates(playerID string, program *tea.Program) {
 	defer ticker.Stop()
 
 	for range ticker.C {
-		// Check if player is in a lobby that's ready to start
-		if lobby, exists := s.manager.GetLobby(playerID); exists {
+		// While in a lobby the player's session ID is the lobby's
+		id, exists := s.manager.PlayerSessionID(playerID)
+		if !exists {
+			return
+		}
+
+		// Start the race once the player's lobby is ready
+		if lobby, exists := s.manager.GetLobby(id); exists {
 			if lobby.IsReady() {
-				// Start session from lobby
 				session, err := s.manager.StartSessionFromLobby(lobby.ID)
 				if err != nil {
 					log.Printf("Failed to start session from lobby: %v", err)
@@ -162,17 +234,13 @@ func (s *SSHServer) handlePlayerUpdates(playerID string, program *tea.Program) {
 				program.Send(ui.StartGameMsg{SessionID: session.ID})
 				return
 			}
+			continue
 		}
 
-		// Check if player is in an active session
-		if session, exists := s.manager.GetSession(playerID); exists {
-			// Session is active, send refresh message
-			program.Send(ui.RefreshGameMsg{})
-
-			// Check if session is finished
-			if session.IsFinished {
-				return
-			}
+		// Another player in the lobby started the race
+		if _, exists := s.manager.GetSession(id); exists {
+			program.Send(ui.StartGameMsg{SessionID: id})
+			return
 		}
 	}
 }
@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Terminals draw text left to right in the order it is written, so Arabic
+// and Hebrew passages are reordered for display here. This follows the
+// Unicode bidirectional algorithm closely enough for prose: strong letters,
+// numbers and the neutral characters between them, without explicit
+// embeddings or isolates.
+
+// direction is the resolved direction of a grapheme cluster
+type direction int
+
+const (
+	neutral direction = iota
+	leftToRight
+	rightToLeft
+	number
+)
+
+// directionOf returns the direction of a grapheme cluster's first rune
+func directionOf(cluster string) direction {
+	props, _ := bidi.LookupString(cluster)
+	switch props.Class() {
+	case bidi.L:
+		return leftToRight
+	case bidi.R, bidi.AL:
+		return rightToLeft
+	case bidi.EN, bidi.AN:
+		return number
+	default:
+		return neutral
+	}
+}
+
+// isRTL reports whether text reads right to left, judged by its first
+// letter with a strong direction
+func isRTL(s string) bool {
+	for s != "" {
+		var cluster string
+		cluster, s, _, _ = uniseg.FirstGraphemeClusterInString(s, -1)
+		switch directionOf(cluster) {
+		case leftToRight:
+			return false
+		case rightToLeft:
+			return true
+		}
+	}
+	return false
+}
+
+// hasRTL reports whether any cluster is written right to left
+func hasRTL(clusters []string) bool {
+	for _, cluster := range clusters {
+		if directionOf(cluster) == rightToLeft {
+			return true
+		}
+	}
+	return false
+}
+
+// bidiLevels resolves the embedding level of every cluster of one line:
+// even levels read left to right and odd levels right to left
+func bidiLevels(clusters []string, rtl bool) []int {
+	base := 0
+	if rtl {
+		base = 1
+	}
+	// The even level left-to-right text takes in this paragraph
+	ltrLevel := base + base%2
+
+	directions := make([]direction, len(clusters))
+	prev := rightToLeft
+	if !rtl {
+		prev = leftToRight
+	}
+	for i, cluster := range clusters {
+		directions[i] = directionOf(cluster)
+		switch directions[i] {
+		case leftToRight, rightToLeft:
+			prev = directions[i]
+		case number:
+			// Numbers follow the letters before them, but always read left
+			// to right
+			if prev == leftToRight {
+				directions[i] = leftToRight
+			}
+		}
+	}
+
+	levels := make([]int, len(clusters))
+	for i, d := range directions {
+		switch d {
+		case leftToRight:
+			levels[i] = ltrLevel
+		case rightToLeft:
+			levels[i] = 1
+		case number:
+			levels[i] = 2
+		}
+	}
+
+	// Neutrals between text of one direction take that direction, and
+	// otherwise the paragraph's
+	for i := 0; i < len(directions); {
+		if directions[i] != neutral {
+			i++
+			continue
+		}
+		end := i
+		for end < len(directions) && directions[end] == neutral {
+			end++
+		}
+
+		before, after := base, base
+		if i > 0 {
+			before = sideLevel(directions[i-1], ltrLevel)
+		}
+		if end < len(directions) {
+			after = sideLevel(directions[end], ltrLevel)
+		}
+		level := base
+		if before%2 == after%2 {
+			level = min(before, after)
+			if level%2 == 0 {
+				level = ltrLevel
+			}
+		}
+		for j := i; j < end; j++ {
+			levels[j] = level
+		}
+		i = end
+	}
+	return levels
+}
+
+// sideLevel returns the level a neutral next to a cluster of direction d
+// would take from it; numbers count as right to left
+func sideLevel(d direction, ltrLevel int) int {
+	if d == leftToRight {
+		return ltrLevel
+	}
+	return 1
+}
+
+// visualOrder returns the indexes of one line's clusters in the order they
+// are displayed, from left to right
+func visualOrder(levels []int) []int {
+	order := make([]int, len(levels))
+	highest, lowestOdd := 0, -1
+	for i, level := range levels {
+		order[i] = i
+		highest = max(highest, level)
+		if level%2 == 1 && (lowestOdd < 0 || level < lowestOdd) {
+			lowestOdd = level
+		}
+	}
+	if lowestOdd < 0 {
+		return order
+	}
+
+	// Reverse every run at each level or above, highest level first
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(order); {
+			if levels[order[i]] < level {
+				i++
+				continue
+			}
+			end := i
+			for end < len(order) && levels[order[end]] >= level {
+				end++
+			}
+			for a, b := i, end-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = end
+		}
+	}
+	return order
+}
+
+// mirroredBrackets maps brackets to the glyph shown in right-to-left text
+var mirroredBrackets = map[string]string{
+	"(": ")", ")": "(", "[": "]", "]": "[", "{": "}", "}": "{",
+	"<": ">", ">": "<", "«": "»", "»": "«",
+}
+
+// layoutBidi lays out the clusters of text containing right-to-left script,
+// rendering the cluster at each index with render: lines are wrapped to
+// width, reordered for display and right-to-left lines are aligned right
+func layoutBidi(clusters []string, render func(i int, cluster string) string, width int) string {
+	// Leave room for the marker shown on mistyped newlines
+	width = max(width-1, 1)
+
+	var out strings.Builder
+	start := 0
+	for i := 0; i <= len(clusters); i++ {
+		if i < len(clusters) && clusters[i] != "\n" {
+			continue
+		}
+
+		line := clusters[start:i]
+		rtl := isRTL(strings.Join(line, ""))
+		for j, span := range wrapClusters(line, width) {
+			if j > 0 {
+				out.WriteString("\n")
+			}
+			out.WriteString(renderBidiLine(clusters, start+span[0], start+span[1], render, rtl, width))
+		}
+		if i < len(clusters) {
+			out.WriteString(render(i, clusters[i]))
+		}
+		start = i + 1
+	}
+	return out.String()
+}
+
+// renderBidiLine renders the clusters from start to end, one wrapped line,
+// in display order
+func renderBidiLine(all []string, start, end int, render func(i int, cluster string) string, rtl bool, width int) string {
+	clusters := all[start:end]
+	levels := bidiLevels(clusters, rtl)
+
+	// Spaces at the end of a line keep the paragraph's direction
+	base := 0
+	if rtl {
+		base = 1
+	}
+	for i := len(clusters) - 1; i >= 0 && strings.TrimSpace(clusters[i]) == ""; i-- {
+		levels[i] = base
+	}
+
+	var line strings.Builder
+	used := 0
+	for _, i := range visualOrder(levels) {
+		cluster := clusters[i]
+		if mirrored, ok := mirroredBrackets[cluster]; ok && levels[i]%2 == 1 {
+			cluster = mirrored
+		}
+		line.WriteString(render(start+i, cluster))
+		used += clusterWidth(cluster)
+	}
+
+	if rtl && used < width {
+		return strings.Repeat(" ", width-used) + line.String()
+	}
+	return line.String()
+}
+
+// wrapClusters splits a line into spans of at most width columns, breaking
+// after spaces where possible
+func wrapClusters(clusters []string, width int) [][2]int {
+	var spans [][2]int
+	start, used, lastSpace := 0, 0, -1
+	for i, cluster := range clusters {
+		w := clusterWidth(cluster)
+		if used+w > width && i > start {
+			end := i
+			if lastSpace >= start {
+				end = lastSpace + 1
+			}
+			spans = append(spans, [2]int{start, end})
+			start, lastSpace = end, -1
+			used = 0
+			for _, c := range clusters[start:i] {
+				used += clusterWidth(c)
+			}
+		}
+		used += w
+		if cluster == " " {
+			lastSpace = i
+		}
+	}
+	return append(spans, [2]int{start, len(clusters)})
+}
+
+// clusterWidth returns how many columns a cluster takes on screen
+func clusterWidth(cluster string) int {
+	if cluster == "\t" {
+		return 4
+	}
+	return uniseg.StringWidth(cluster)
+}
@@ -0,0 +1,361 @@
+package ui
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"typeracer-tui/game"
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/keyboard"
+	"typeracer-tui/lessons"
+	"typeracer-tui/quotes"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lessonLength is roughly how many characters each lesson run is
+const lessonLength = 120
+
+// lessonScreen is what the lessons mode is showing
+type lessonScreen int
+
+const (
+	lessonMenu lessonScreen = iota
+	lessonTyping
+	lessonResults
+)
+
+// LessonsModel is the touch-typing course: a menu of lessons, each unlocked
+// by passing the one before, and a typing screen for the chosen lesson
+type LessonsModel struct {
+	lessons    []lessons.Lesson
+	progress   *lessons.Progress
+	layout     *keyboard.Layout
+	rng        *rand.Rand
+	screen     lessonScreen
+	cursor     int
+	prompt     string
+	typedInput string
+	startTime  time.Time
+	endTime    time.Time
+	keystrokes []scoring.Keystroke
+	score      scoring.Score
+	metric     scoring.Metric
+	passed     bool
+	saveErr    error
+	width      int
+	height     int
+}
+
+// NewLessonsModel creates the lessons mode for a keyboard layout, recording
+// results in progress
+func NewLessonsModel(progress *lessons.Progress, layout *keyboard.Layout) *LessonsModel {
+	m := &LessonsModel{
+		lessons:  lessons.CurriculumFor(layout),
+		progress: progress,
+		layout:   layout,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		metric:   scoring.DefaultMetric,
+		width:    80,
+		height:   24,
+	}
+
+	// Start on the first lesson not yet passed
+	for m.cursor < len(m.lessons)-1 && progress.Get(m.lessons[m.cursor].ID).Passed {
+		m.cursor++
+	}
+	return m
+}
+
+// SetMetric sets the speed figure shown as the headline while typing
+func (m *LessonsModel) SetMetric(metric scoring.Metric) {
+	m.metric = metric
+}
+
+// Init initializes the lessons model
+func (m *LessonsModel) Init() tea.Cmd {
+	return tea.EnterAltScreen
+}
+
+// Update handles messages and updates the model
+func (m *LessonsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+		switch m.screen {
+		case lessonMenu:
+			return m.updateMenu(msg)
+		case lessonTyping:
+			m.updateTyping(msg)
+		case lessonResults:
+			return m.updateResults(msg)
+		}
+	}
+
+	return m, nil
+}
+
+// updateMenu handles keys on the lesson list
+func (m *LessonsModel) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.lessons)-1 {
+			m.cursor++
+		}
+	case "enter", " ":
+		if m.progress.Unlocked(m.lessons, m.cursor) {
+			m.start()
+		}
+	}
+	return m, nil
+}
+
+// updateTyping handles keys while a lesson is being typed
+func (m *LessonsModel) updateTyping(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc":
+		m.screen = lessonMenu
+	case "backspace":
+		if typed, key, ok := game.PressBackspace(m.prompt, m.typedInput); ok {
+			m.typedInput = typed
+			m.keystrokes = append(m.keystrokes, key)
+			m.updateStats()
+		}
+	default:
+		text, ok := typedText(msg, m.prompt, m.layout)
+		if !ok {
+			return
+		}
+		// The clock starts with the first key so there is time to find the
+		// home row, and keeps running if the player backspaces to the start
+		if m.startTime.IsZero() {
+			m.startTime = time.Now()
+		}
+		typed, key := game.PressKey(m.prompt, m.typedInput, text)
+		m.typedInput = typed
+		m.keystrokes = append(m.keystrokes, key)
+		m.updateStats()
+
+		if quotes.GraphemeCount(m.typedInput) >= quotes.GraphemeCount(m.prompt) {
+			m.finish()
+		}
+	}
+}
+
+// updateResults handles keys after a lesson is finished
+func (m *LessonsModel) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "r", "enter":
+		m.start()
+	case "n":
+		if m.cursor < len(m.lessons)-1 && m.progress.Unlocked(m.lessons, m.cursor+1) {
+			m.cursor++
+			m.start()
+		}
+	case "m", "esc":
+		m.screen = lessonMenu
+	}
+	return m, nil
+}
+
+// start generates fresh text for the selected lesson
+func (m *LessonsModel) start() {
+	lesson := m.lessons[m.cursor]
+	m.prompt = lessons.Generate(lessons.AllowedKeys(m.lessons, m.cursor), lesson.Keys, lessonLength, m.rng)
+	m.typedInput = ""
+	m.keystrokes = nil
+	m.startTime, m.endTime = time.Time{}, time.Time{}
+	m.score = scoring.Score{}
+	m.saveErr = nil
+	m.screen = lessonTyping
+}
+
+// updateStats scores the typing so far, or up to the end once finished
+func (m *LessonsModel) updateStats() {
+	end := m.endTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	m.score = scoring.Compute(m.prompt, m.typedInput, m.keystrokes, m.startTime, end)
+}
+
+// finish records the run and shows whether the lesson was passed
+func (m *LessonsModel) finish() {
+	m.endTime = time.Now()
+	m.updateStats()
+	m.screen = lessonResults
+	m.passed, m.saveErr = m.progress.Record(m.lessons[m.cursor], m.score.NetWPM, m.score.Accuracy)
+}
+
+// View renders the lessons UI
+func (m *LessonsModel) View() string {
+	switch m.screen {
+	case lessonTyping:
+		return m.renderTyping()
+	case lessonResults:
+		return m.renderResults()
+	}
+	return m.renderMenu()
+}
+
+// renderMenu renders the list of lessons with their status
+func (m *LessonsModel) renderMenu() string {
+	var content strings.Builder
+
+	content.WriteString(TitleStyle.Render(fmt.Sprintf("Touch Typing Lessons (%s)", m.layout.Name)))
+	content.WriteString("\n\n")
+
+	var list strings.Builder
+	var stage lessons.Stage
+	for i, lesson := range m.lessons {
+		if lesson.Stage != stage {
+			stage = lesson.Stage
+			if i > 0 {
+				list.WriteString("\n")
+			}
+			list.WriteString(PlayerNameStyle.Render(string(stage)))
+			list.WriteString("\n")
+		}
+
+		record := m.progress.Get(lesson.ID)
+		status := "  "
+		switch {
+		case record.Passed:
+			status = "✓ "
+		case !m.progress.Unlocked(m.lessons, i):
+			status = "🔒"
+		}
+
+		line := fmt.Sprintf("%s %-28s keys: %s", status, lesson.Name, lesson.Keys)
+		if record.Attempts > 0 {
+			line += fmt.Sprintf("  best %s, %s", FormatWPM(record.BestWPM), FormatAccuracy(record.BestAccuracy))
+		}
+
+		switch {
+		case i == m.cursor:
+			list.WriteString(CurrentTextStyle.Render("> " + line))
+		case record.Passed:
+			list.WriteString(CorrectTextStyle.Render("  " + line))
+		default:
+			list.WriteString(UntypedTextStyle.Render("  " + line))
+		}
+		list.WriteString("\n")
+	}
+	content.WriteString(MainBoxStyle.Width(m.width - 4).Render(strings.TrimRight(list.String(), "\n")))
+	content.WriteString("\n\n")
+
+	lesson := m.lessons[m.cursor]
+	if m.progress.Unlocked(m.lessons, m.cursor) {
+		content.WriteString(SubtitleStyle.Render(fmt.Sprintf(
+			"To pass: %s at %s accuracy", FormatWPM(lesson.MinWPM), FormatAccuracy(lesson.MinAccuracy),
+		)))
+	} else {
+		content.WriteString(SubtitleStyle.Render("Pass the previous lesson to unlock this one"))
+	}
+	content.WriteString("\n\n")
+
+	content.WriteString(InstructionStyle.Render("↑/↓ to choose a lesson, Enter to start, 'q' to quit"))
+	return content.String()
+}
+
+// renderTyping renders the lesson typing screen
+func (m *LessonsModel) renderTyping() string {
+	var content strings.Builder
+	lesson := m.lessons[m.cursor]
+
+	content.WriteString(TitleStyle.Render(lesson.Title()))
+	content.WriteString("\n\n")
+	content.WriteString(SubtitleStyle.Render(fmt.Sprintf(
+		"New keys: %s | Target: %s at %s", lesson.Keys, FormatWPM(lesson.MinWPM), FormatAccuracy(lesson.MinAccuracy),
+	)))
+	content.WriteString("\n\n")
+
+	content.WriteString(MainBoxStyle.Width(m.width - 4).Render(StyleTypingText(m.prompt, m.typedInput, typingWidth(m.width-4))))
+	content.WriteString("\n\n")
+
+	elapsed := time.Duration(0)
+	if !m.startTime.IsZero() {
+		elapsed = time.Since(m.startTime)
+	}
+	content.WriteString(renderScoreStats(m.metric, m.score, elapsed))
+	content.WriteString("\n\n")
+
+	content.WriteString(ProgressBoxStyle.Render(renderProgressBar(m.prompt, quotes.GraphemeCount(m.typedInput), m.width-10)))
+	content.WriteString("\n\n")
+
+	content.WriteString(renderKeyHint(m.layout, m.prompt, m.typedInput))
+	content.WriteString("\n\n")
+
+	content.WriteString(InstructionStyle.Render("Keep your fingers on the home row. Press Esc for the lesson list"))
+	return content.String()
+}
+
+// renderResults renders how the run compared with the lesson's targets
+func (m *LessonsModel) renderResults() string {
+	var content strings.Builder
+	lesson := m.lessons[m.cursor]
+
+	if m.passed {
+		content.WriteString(LeaderboardTitleStyle.Render("Lesson Passed!"))
+	} else {
+		content.WriteString(LeaderboardTitleStyle.Render("Not Quite"))
+	}
+	content.WriteString("\n\n")
+
+	// Lesson targets are in net words per minute, whatever the headline
+	results := fmt.Sprintf(
+		"%s: %s (target %s)\nAccuracy: %s (target %s)\nTime: %s\n\n%s",
+		scoring.MetricNetWPM.Label(), FormatWPM(m.score.NetWPM), FormatWPM(lesson.MinWPM),
+		FormatAccuracy(m.score.Accuracy), FormatAccuracy(lesson.MinAccuracy),
+		FormatDuration(m.endTime.Sub(m.startTime).Seconds()),
+		formatScoreDetails(scoring.MetricNetWPM, m.score),
+	)
+	content.WriteString(MainBoxStyle.Width(m.width - 4).Render(results))
+	content.WriteString("\n\n")
+
+	hasNext := m.cursor < len(m.lessons)-1
+	switch {
+	case m.passed && hasNext:
+		content.WriteString(SuccessStyle.Render("Unlocked: " + m.lessons[m.cursor+1].Title()))
+		content.WriteString("\n\n")
+	case m.passed:
+		content.WriteString(SuccessStyle.Render("You have finished the whole course!"))
+		content.WriteString("\n\n")
+	case m.score.Accuracy < lesson.MinAccuracy:
+		content.WriteString(InstructionStyle.Render("Slow down a little and aim for accuracy first."))
+		content.WriteString("\n\n")
+	default:
+		content.WriteString(InstructionStyle.Render("Accurate! Now try to pick up the pace."))
+		content.WriteString("\n\n")
+	}
+
+	if m.saveErr != nil {
+		content.WriteString(ErrorStyle.Render(fmt.Sprintf("Could not save your progress: %v", m.saveErr)))
+		content.WriteString("\n\n")
+	}
+
+	keys := "Press 'r' to try again"
+	if hasNext && m.progress.Unlocked(m.lessons, m.cursor+1) {
+		keys += ", 'n' for the next lesson"
+	}
+	content.WriteString(InstructionStyle.Render(keys + ", 'm' for the lesson list or 'q' to quit"))
+	return content.String()
+}
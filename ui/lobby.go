@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"typeracer-tui/game"
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/keyboard"
+	"typeracer-tui/quotes"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -18,6 +21,13 @@ type LobbyModel struct {
 	lobbyID       string
 	players       []*game.Player
 	maxPlayers    int
+	difficulty    quotes.Difficulty
+	language      quotes.Language
+	moderator     bool
+	voter         string
+	layout        *keyboard.Layout
+	metric        scoring.Metric
+	notice        string
 	width         int
 	height        int
 	refreshTicker *time.Ticker
@@ -25,17 +35,35 @@ type LobbyModel struct {
 
 // NewLobbyModel creates a new lobby model
 func NewLobbyModel(manager *game.Manager, playerID, playerName, lobbyID string, maxPlayers int) *LobbyModel {
+	language := manager.DefaultLanguage()
+	if lobby, exists := manager.GetLobby(lobbyID); exists {
+		language = lobby.Language
+	}
 	return &LobbyModel{
 		manager:    manager,
 		playerID:   playerID,
 		playerName: playerName,
 		lobbyID:    lobbyID,
 		maxPlayers: maxPlayers,
+		language:   language,
+		layout:     keyboard.QWERTY,
+		metric:     scoring.DefaultMetric,
 		width:      80,
 		height:     24,
 	}
 }
 
+// SetModerator allows the player to review quote submissions
+func (m *LobbyModel) SetModerator(moderator bool) {
+	m.moderator = moderator
+}
+
+// SetVoter sets the key the player's quote votes are kept under; players
+// without one cannot vote
+func (m *LobbyModel) SetVoter(voter string) {
+	m.voter = voter
+}
+
 // Init initializes the lobby model
 func (m *LobbyModel) Init() tea.Cmd {
 	return tea.Batch(
@@ -67,6 +95,43 @@ func (m *LobbyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r":
 			// Refresh lobby
 			return m, m.startRefreshTicker()
+		case "d":
+			// Cycle the difficulty the lobby will race on
+			if err := m.manager.SetLobbyDifficulty(m.lobbyID, m.difficulty.Next()); err == nil {
+				m.difficulty = m.difficulty.Next()
+			}
+		case "l":
+			// Cycle the keyboard layout this player types with
+			m.layout = m.layout.Next()
+		case "w":
+			// Cycle the speed figure shown as the headline
+			m.metric = m.metric.Next()
+		case "g":
+			// Race in another language, which means another lobby
+			if lobby, err := m.manager.SetPlayerLanguage(m.playerID, m.language.Next()); err == nil {
+				m.lobbyID = lobby.ID
+				m.language = lobby.Language
+				m.players = lobby.GetPlayers()
+				m.maxPlayers = lobby.MaxPlayers
+				m.difficulty = lobby.GetDifficulty()
+			}
+		case "h":
+			// Let quotes this player has typed come up again
+			if err := m.manager.ResetHistory(m.playerID); err != nil {
+				m.notice = fmt.Sprintf("Could not reset your quote history: %v", err)
+			} else {
+				m.notice = "Your quote history was reset; typed quotes can come up again"
+			}
+		case "s":
+			// Propose a new quote while waiting
+			if m.manager.Submissions() != nil {
+				return NewSubmitModel(m.manager, m.playerName, m.language, m, m.width, m.height), nil
+			}
+		case "m":
+			// Review submitted quotes
+			if m.canModerate() {
+				return NewModerationModel(m.manager, m.playerName, m, m.width, m.height), nil
+			}
 		}
 		return m, nil
 
@@ -75,12 +140,17 @@ func (m *LobbyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if lobby, exists := m.manager.GetLobby(m.lobbyID); exists {
 			m.players = lobby.GetPlayers()
 			m.maxPlayers = lobby.MaxPlayers
+			m.difficulty = lobby.GetDifficulty()
 		}
 		return m, m.startRefreshTicker()
 
 	case StartGameMsg:
 		// Game is starting, transition to multiplayer mode
-		return NewMultiplayerModel(m.manager, m.playerID, m.playerName, msg.SessionID), nil
+		gameModel := NewMultiplayerModel(m.manager, m.playerID, m.playerName, msg.SessionID)
+		gameModel.SetLayout(m.layout)
+		gameModel.SetMetric(m.metric)
+		gameModel.SetVoter(m.voter)
+		return gameModel, nil
 	}
 
 	return m, nil
@@ -95,7 +165,7 @@ func (m *LobbyModel) View() string {
 	content.WriteString("\n\n")
 
 	// Lobby info
-	lobbyInfo := fmt.Sprintf("Lobby ID: %s", m.lobbyID)
+	lobbyInfo := fmt.Sprintf("Lobby ID: %s | Language: %s | Difficulty: %s | Your layout: %s | Showing: %s", m.lobbyID, m.language.Label(), m.difficulty.Label(), m.layout.Name, m.metric.Label())
 	content.WriteString(SubtitleStyle.Render(lobbyInfo))
 	content.WriteString("\n\n")
 
@@ -107,12 +177,43 @@ func (m *LobbyModel) View() string {
 	content.WriteString(m.renderStatus())
 	content.WriteString("\n\n")
 
+	if m.notice != "" {
+		content.WriteString(SuccessStyle.Render(m.notice))
+		content.WriteString("\n\n")
+	}
+
+	// Pending submissions for moderators
+	if m.canModerate() {
+		if pending := len(m.manager.Submissions().Pending()); pending > 0 {
+			content.WriteString(SuccessStyle.Render(fmt.Sprintf("%d submitted quotes waiting for review", pending)))
+			content.WriteString("\n")
+		}
+	}
+
 	// Instructions
-	content.WriteString(InstructionStyle.Render("Waiting for players... Press 'r' to refresh, 'q' to quit"))
+	content.WriteString(InstructionStyle.Render("Waiting for players... " + m.renderKeys()))
 
 	return content.String()
 }
 
+// canModerate reports whether the player may review quote submissions
+func (m *LobbyModel) canModerate() bool {
+	return m.moderator && m.manager.Submissions() != nil
+}
+
+// renderKeys lists the lobby controls available to the player
+func (m *LobbyModel) renderKeys() string {
+	keys := []string{"'d' to change difficulty", "'g' to change language", "'l' to change keyboard layout", "'w' to change speed metric", "'h' to reset your quote history"}
+	if m.manager.Submissions() != nil {
+		keys = append(keys, "'s' to submit a quote")
+	}
+	if m.canModerate() {
+		keys = append(keys, "'m' to review submissions")
+	}
+	keys = append(keys, "'r' to refresh", "'q' to quit")
+	return "Press " + strings.Join(keys, ", ")
+}
+
 // renderPlayersList renders the list of connected players
 func (m *LobbyModel) renderPlayersList() string {
 	var content strings.Builder
@@ -0,0 +1,264 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"typeracer-tui/game"
+	"typeracer-tui/quotes"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ModerationModel lets an admin approve, edit or reject submitted quotes
+type ModerationModel struct {
+	manager   *game.Manager
+	reviewer  string
+	lobby     tea.Model
+	pending   []quotes.Submission
+	cursor    int
+	editing   *quoteForm
+	rejecting bool
+	reason    []rune
+	message   string
+	err       error
+	width     int
+	height    int
+}
+
+// NewModerationModel creates a review screen that returns to lobby when closed
+func NewModerationModel(manager *game.Manager, reviewer string, lobby tea.Model, width, height int) *ModerationModel {
+	m := &ModerationModel{
+		manager:  manager,
+		reviewer: reviewer,
+		lobby:    lobby,
+		width:    width,
+		height:   height,
+	}
+	m.reload()
+	return m
+}
+
+// Init initializes the review screen
+func (m *ModerationModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m *ModerationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.lobby.Update(msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		m.message, m.err = "", nil
+
+		switch {
+		case m.editing != nil:
+			m.updateEdit(msg)
+		case m.rejecting:
+			m.updateReject(msg)
+		default:
+			return m.updateList(msg)
+		}
+		return m, nil
+
+	case StartGameMsg:
+		// The race starts without waiting for the review
+		return m.lobby.Update(msg)
+
+	case RefreshLobbyMsg:
+		// Keep the lobby up to date underneath the review screen
+		_, cmd := m.lobby.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// updateList handles keys while browsing the pending submissions
+func (m *ModerationModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return m.lobby, nil
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.pending)-1 {
+			m.cursor++
+		}
+	case "r":
+		m.reload()
+	case "a":
+		if submission, ok := m.selected(); ok {
+			if m.err = m.manager.Submissions().Approve(submission.ID, m.reviewer); m.err == nil {
+				m.message = "Approved; it will now come up in races."
+			}
+			m.reload()
+		}
+	case "e":
+		if submission, ok := m.selected(); ok {
+			m.editing = newQuoteForm(submission.Content, submission.Author, submission.Tags)
+		}
+	case "x":
+		if _, ok := m.selected(); ok {
+			m.rejecting = true
+			m.reason = nil
+		}
+	}
+	return m, nil
+}
+
+// updateEdit handles keys while correcting a submission
+func (m *ModerationModel) updateEdit(msg tea.KeyMsg) {
+	if msg.String() == "esc" {
+		m.editing = nil
+		return
+	}
+	if !m.editing.update(msg) {
+		return
+	}
+
+	submission, ok := m.selected()
+	if !ok {
+		m.editing = nil
+		return
+	}
+	content, author, tags := m.editing.values()
+	if m.err = m.manager.Submissions().Edit(submission.ID, content, author, tags); m.err != nil {
+		return
+	}
+	m.editing = nil
+	m.message = "Changes saved; press 'a' to approve."
+	m.reload()
+}
+
+// updateReject handles keys while typing a rejection reason
+func (m *ModerationModel) updateReject(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc":
+		m.rejecting = false
+	case "enter":
+		m.rejecting = false
+		if submission, ok := m.selected(); ok {
+			if m.err = m.manager.Submissions().Reject(submission.ID, m.reviewer, string(m.reason)); m.err == nil {
+				m.message = "Rejected."
+			}
+			m.reload()
+		}
+	case "backspace":
+		if len(m.reason) > 0 {
+			m.reason = m.reason[:len(m.reason)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.reason = append(m.reason, msg.Runes...)
+		}
+	}
+}
+
+// reload refreshes the pending submissions, keeping the cursor in range
+func (m *ModerationModel) reload() {
+	m.pending = nil
+	if submissions := m.manager.Submissions(); submissions != nil {
+		m.pending = submissions.Pending()
+	}
+	if m.cursor >= len(m.pending) {
+		m.cursor = max(len(m.pending)-1, 0)
+	}
+}
+
+// selected returns the submission under the cursor
+func (m *ModerationModel) selected() (quotes.Submission, bool) {
+	if m.cursor >= len(m.pending) {
+		return quotes.Submission{}, false
+	}
+	return m.pending[m.cursor], true
+}
+
+// View renders the review screen
+func (m *ModerationModel) View() string {
+	var content strings.Builder
+
+	content.WriteString(TitleStyle.Render("Quote Submissions"))
+	content.WriteString("\n\n")
+	content.WriteString(SubtitleStyle.Render(fmt.Sprintf("%d waiting for review", len(m.pending))))
+	content.WriteString("\n\n")
+
+	switch {
+	case m.editing != nil:
+		content.WriteString(m.editing.view(m.width))
+	case len(m.pending) == 0:
+		content.WriteString(MainBoxStyle.Width(m.width - 4).Render(InstructionStyle.Render("Nothing to review")))
+	default:
+		content.WriteString(m.renderPending())
+	}
+	content.WriteString("\n")
+
+	if m.err != nil {
+		content.WriteString(ErrorStyle.Render(m.err.Error()))
+		content.WriteString("\n")
+	} else if m.message != "" {
+		content.WriteString(SuccessStyle.Render(m.message))
+		content.WriteString("\n")
+	}
+
+	switch {
+	case m.editing != nil:
+		content.WriteString(InstructionStyle.Render("Tab to switch fields, Enter on tags or Ctrl+S to save, Esc to cancel"))
+	case m.rejecting:
+		content.WriteString(PlayerNameStyle.Render("Reason: " + string(m.reason) + "█"))
+		content.WriteString("\n")
+		content.WriteString(InstructionStyle.Render("Enter to reject, Esc to cancel"))
+	default:
+		content.WriteString(InstructionStyle.Render("↑/↓ to choose, 'a' approve, 'e' edit, 'x' reject, 'r' refresh, Esc to go back"))
+	}
+
+	return content.String()
+}
+
+// renderPending lists the pending submissions with the selected one in full
+func (m *ModerationModel) renderPending() string {
+	var content strings.Builder
+
+	for i, submission := range m.pending {
+		line := fmt.Sprintf("%s  %-12s %s", submission.SubmittedAt.Format("Jan 02 15:04"), submission.SubmittedBy, truncate(submission.Content, max(m.width-40, 20)))
+		if i == m.cursor {
+			content.WriteString(PlayerNameStyle.Render("> " + line))
+		} else {
+			content.WriteString(UntypedTextStyle.Render("  " + line))
+		}
+		content.WriteString("\n")
+	}
+
+	submission := m.pending[m.cursor]
+	content.WriteString("\n")
+	content.WriteString(CorrectTextStyle.Render(submission.Content))
+	content.WriteString("\n")
+	attribution := "— " + submission.Author
+	if len(submission.Tags) > 0 {
+		attribution += " [" + strings.Join(submission.Tags, ", ") + "]"
+	}
+	quote := submission.Quote()
+	attribution += " (" + quotes.LanguageOf(&quote).Label() + ")"
+	content.WriteString(InstructionStyle.UnsetMargins().Render(attribution))
+
+	return MainBoxStyle.Width(m.width - 4).Render(content.String())
+}
+
+// truncate shortens text to at most limit runes on one line
+func truncate(text string, limit int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return string(runes[:max(limit-3, 0)]) + "..."
+}
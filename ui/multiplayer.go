@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"typeracer-tui/game"
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/keyboard"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -15,17 +17,17 @@ type MultiplayerModel struct {
 	manager       *game.Manager
 	playerID      string
 	playerName    string
+	voter         string
 	sessionID     string
 	session       *game.Session
-	typedInput    string
-	startTime     time.Time
-	isFinished    bool
-	wpm           float64
-	accuracy      float64
-	correctChars  int
+	status        game.SessionStatus
+	you           game.Player
 	width         int
 	height        int
 	showResults   bool
+	voteErr       error
+	layout        *keyboard.Layout
+	metric        scoring.Metric
 	refreshTicker *time.Ticker
 }
 
@@ -36,11 +38,29 @@ func NewMultiplayerModel(manager *game.Manager, playerID, playerName, sessionID
 		playerID:   playerID,
 		playerName: playerName,
 		sessionID:  sessionID,
+		layout:     keyboard.QWERTY,
+		metric:     scoring.DefaultMetric,
 		width:      80,
 		height:     24,
 	}
 }
 
+// SetLayout sets the keyboard layout the player's keys are remapped to
+func (m *MultiplayerModel) SetLayout(layout *keyboard.Layout) {
+	m.layout = layout
+}
+
+// SetVoter sets the key the player's quote votes are kept under; players
+// without one cannot vote
+func (m *MultiplayerModel) SetVoter(voter string) {
+	m.voter = voter
+}
+
+// SetMetric sets the speed figure shown as the headline
+func (m *MultiplayerModel) SetMetric(metric scoring.Metric) {
+	m.metric = metric
+}
+
 // Init initializes the multiplayer model
 func (m *MultiplayerModel) Init() tea.Cmd {
 	return tea.Batch(
@@ -71,23 +91,29 @@ func (m *MultiplayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "q", "ctrl+c", "esc":
 				return m, tea.Quit
 			}
+			if vote, ok := voteKeys[msg.String()]; ok {
+				m.voteErr = m.manager.RateQuote(m.sessionID, m.playerID, m.voter, vote)
+			}
 		} else {
 			switch msg.String() {
 			case "ctrl+c", "esc":
 				return m, tea.Quit
+			}
+			if m.session == nil {
+				return m, nil
+			}
+
+			// The session decides whether a key counts and scores it; this
+			// model only shows what the session reports
+			switch msg.String() {
 			case "backspace":
-				if len(m.typedInput) > 0 {
-					m.typedInput = m.typedInput[:len(m.typedInput)-1]
-					m.updateProgress()
+				if m.manager.Backspace(m.playerID) == nil {
+					m.refresh()
 				}
 			default:
-				if len(msg.String()) == 1 {
-					m.typedInput += msg.String()
-					m.updateProgress()
-
-					// Check if finished
-					if m.isComplete() && !m.isFinished {
-						m.finish()
+				if text, ok := typedText(msg, m.session.Prompt, m.layout); ok {
+					if m.manager.TypeKey(m.playerID, text) == nil {
+						m.refresh()
 					}
 				}
 			}
@@ -98,14 +124,10 @@ func (m *MultiplayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update session state
 		if session, exists := m.manager.GetSession(m.sessionID); exists {
 			m.session = session
-
-			// Check if game has started
-			if session.IsActive && session.Countdown == 0 && m.startTime.IsZero() {
-				m.startTime = time.Now()
-			}
+			m.refresh()
 
 			// Check if game is finished
-			if session.IsFinished && !m.showResults {
+			if m.status.IsFinished && !m.showResults {
 				m.showResults = true
 			}
 		}
@@ -115,6 +137,15 @@ func (m *MultiplayerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// refresh reads the session's status and this player's state from the
+// session
+func (m *MultiplayerModel) refresh() {
+	m.status = m.session.GetStatus()
+	if you, exists := m.session.Snapshot(m.playerID); exists {
+		m.you = you
+	}
+}
+
 // View renders the multiplayer game UI
 func (m *MultiplayerModel) View() string {
 	if m.session == nil {
@@ -125,7 +156,7 @@ func (m *MultiplayerModel) View() string {
 		return m.renderResults()
 	}
 
-	if m.session.Countdown > 0 {
+	if m.status.Countdown > 0 {
 		return m.renderCountdown()
 	}
 
@@ -141,7 +172,7 @@ func (m *MultiplayerModel) renderCountdown() string {
 	content.WriteString("\n\n")
 
 	// Countdown
-	countdownText := fmt.Sprintf("%d", m.session.Countdown)
+	countdownText := fmt.Sprintf("%d", m.status.Countdown)
 	content.WriteString(CountdownStyle.Render(countdownText))
 	content.WriteString("\n\n")
 
@@ -171,7 +202,7 @@ func (m *MultiplayerModel) renderGame() string {
 
 	// Typing area
 	typingBox := MainBoxStyle.Width(m.width - 4).Render(
-		StyleTypingText(m.session.Prompt, m.typedInput),
+		StyleTypingText(m.session.Prompt, m.you.TypedInput, typingWidth(m.width-4)),
 	)
 	content.WriteString(typingBox)
 	content.WriteString("\n\n")
@@ -182,16 +213,26 @@ func (m *MultiplayerModel) renderGame() string {
 	content.WriteString("\n\n")
 
 	// Progress bar
-	progress := CreateProgressBar(len(m.typedInput), len(m.session.Prompt), m.width-10)
+	progress := renderProgressBar(m.session.Prompt, m.you.CurrentPos, m.width-10)
 	content.WriteString(ProgressBoxStyle.Render(progress))
 	content.WriteString("\n\n")
 
+	// Which key to press next on an emulated layout
+	if !m.layout.IsQWERTY() {
+		content.WriteString(renderKeyHint(m.layout, m.session.Prompt, m.you.TypedInput))
+		content.WriteString("\n\n")
+	}
+
 	// Opponents
 	content.WriteString(m.renderOpponents())
 	content.WriteString("\n\n")
 
 	// Instructions
-	content.WriteString(InstructionStyle.Render("Type as fast and accurately as possible!"))
+	if m.you.IsFinished {
+		content.WriteString(SuccessStyle.Render("Finished! Waiting for the other racers..."))
+	} else {
+		content.WriteString(InstructionStyle.Render("Type as fast and accurately as possible!"))
+	}
 
 	return content.String()
 }
@@ -212,6 +253,22 @@ func (m *MultiplayerModel) renderResults() string {
 	content.WriteString(m.renderYourResults())
 	content.WriteString("\n\n")
 
+	// All-time results for this passage
+	if m.session.QuoteID != "" {
+		stats, _ := m.manager.QuoteStats(m.session.QuoteID)
+		yourSeconds := 0.0
+		if m.you.IsFinished {
+			yourSeconds = m.you.Elapsed().Seconds()
+		}
+		content.WriteString(renderQuoteRecords(stats, yourSeconds, m.width-4))
+		content.WriteString("\n\n")
+
+		if rating, vote, enabled := m.manager.QuoteRating(m.session.QuoteID, m.voter); enabled {
+			content.WriteString(renderRating(rating, vote, m.voteErr))
+			content.WriteString("\n\n")
+		}
+	}
+
 	// Instructions
 	content.WriteString(InstructionStyle.Render("Press 'q' to quit"))
 
@@ -220,28 +277,14 @@ func (m *MultiplayerModel) renderResults() string {
 
 // renderStats renders the current stats
 func (m *MultiplayerModel) renderStats() string {
-	var stats strings.Builder
-
-	// WPM
-	stats.WriteString(WPMTextStyle.Render(fmt.Sprintf("WPM: %s", FormatWPM(m.wpm))))
-	stats.WriteString("  ")
-
-	// Accuracy
-	stats.WriteString(AccuracyTextStyle.Render(fmt.Sprintf("Accuracy: %s", FormatAccuracy(m.accuracy))))
-	stats.WriteString("  ")
-
-	// Time
-	elapsed := time.Since(m.startTime).Seconds()
-	stats.WriteString(TimeTextStyle.Render(fmt.Sprintf("Time: %s", FormatDuration(elapsed))))
-
-	return StatsBoxStyle.Render(stats.String())
+	return renderScoreStats(m.metric, m.you.Score, m.you.Elapsed())
 }
 
 // renderPlayersList renders the list of players
 func (m *MultiplayerModel) renderPlayersList() string {
 	var content strings.Builder
 
-	players := m.session.GetPlayers()
+	players := m.session.Snapshots()
 	content.WriteString(PlayerNameStyle.Render(fmt.Sprintf("Players (%d)", len(players))))
 	content.WriteString("\n")
 
@@ -264,7 +307,7 @@ func (m *MultiplayerModel) renderOpponents() string {
 	content.WriteString(PlayerNameStyle.Render("Opponents"))
 	content.WriteString("\n")
 
-	players := m.session.GetPlayers()
+	players := m.session.Snapshots()
 	for i, player := range players {
 		if player.ID == m.playerID {
 			continue // Skip self
@@ -280,12 +323,12 @@ func (m *MultiplayerModel) renderOpponents() string {
 		content.WriteString("\n")
 
 		// Progress bar
-		progress := CreateProgressBar(player.CurrentPos, len(m.session.Prompt), 30)
+		progress := renderProgressBar(m.session.Prompt, player.CurrentPos, 30)
 		content.WriteString(ProgressBoxStyle.Render(progress))
 		content.WriteString("\n")
 
 		// WPM
-		content.WriteString(PlayerWPMStyle.Render(fmt.Sprintf("WPM: %s", FormatWPM(player.WPM))))
+		content.WriteString(PlayerWPMStyle.Render(fmt.Sprintf("%s: %s", m.metric.Label(), m.metric.Format(player.Score))))
 		content.WriteString("\n\n")
 	}
 
@@ -323,9 +366,10 @@ func (m *MultiplayerModel) renderLeaderboard() string {
 		content.WriteString("\n")
 
 		// Stats
-		stats := fmt.Sprintf("WPM: %s | Accuracy: %s",
-			FormatWPM(player.WPM),
-			FormatAccuracy(player.Accuracy))
+		stats := fmt.Sprintf("%s: %s | Accuracy: %s",
+			m.metric.Label(),
+			m.metric.Format(player.Score),
+			FormatAccuracy(player.Score.Accuracy))
 		content.WriteString(LeaderboardWPMStyle.Render(stats))
 		content.WriteString("\n\n")
 	}
@@ -336,71 +380,16 @@ func (m *MultiplayerModel) renderLeaderboard() string {
 // renderYourResults renders your personal results
 func (m *MultiplayerModel) renderYourResults() string {
 	results := fmt.Sprintf(
-		"Your Results:\nWPM: %s | Accuracy: %s | Time: %s",
-		FormatWPM(m.wpm),
-		FormatAccuracy(m.accuracy),
-		FormatDuration(time.Since(m.startTime).Seconds()),
+		"Your Results:\n%s: %s | Accuracy: %s | Time: %s\n\n%s",
+		m.metric.Label(),
+		m.metric.Format(m.you.Score),
+		FormatAccuracy(m.you.Score.Accuracy),
+		FormatDuration(m.you.Elapsed().Seconds()),
+		formatScoreDetails(m.metric, m.you.Score),
 	)
 
 	return MainBoxStyle.Width(m.width - 4).Render(results)
 }
 
-// updateProgress updates the player's progress
-func (m *MultiplayerModel) updateProgress() {
-	if m.session == nil {
-		return
-	}
-
-	// Update local stats
-	m.calculateStats()
-
-	// Update in game manager
-	m.manager.UpdatePlayerProgress(m.playerID, m.typedInput)
-}
-
-// calculateStats calculates local statistics
-func (m *MultiplayerModel) calculateStats() {
-	if m.session == nil {
-		return
-	}
-
-	// Calculate accuracy
-	correct := 0
-	total := len(m.typedInput)
-	if total > len(m.session.Prompt) {
-		total = len(m.session.Prompt)
-	}
-
-	for i := 0; i < total; i++ {
-		if i < len(m.typedInput) && i < len(m.session.Prompt) && m.typedInput[i] == m.session.Prompt[i] {
-			correct++
-		}
-	}
-
-	m.correctChars = correct
-
-	if total > 0 {
-		m.accuracy = float64(correct) / float64(total) * 100.0
-	} else {
-		m.accuracy = 0.0
-	}
-
-	// Calculate WPM
-	elapsed := time.Since(m.startTime).Minutes()
-	if elapsed > 0 {
-		m.wpm = float64(correct) / 5.0 / elapsed
-	}
-}
-
-// isComplete checks if the typing is complete
-func (m *MultiplayerModel) isComplete() bool {
-	return len(m.typedInput) >= len(m.session.Prompt)
-}
-
-// finish marks the player as finished
-func (m *MultiplayerModel) finish() {
-	m.isFinished = true
-}
-
 // RefreshGameMsg represents a message to refresh game state
 type RefreshGameMsg struct{}
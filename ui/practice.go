@@ -5,6 +5,9 @@ import (
 	"strings"
 	"time"
 
+	"typeracer-tui/game"
+	"typeracer-tui/game/scoring"
+	"typeracer-tui/keyboard"
 	"typeracer-tui/quotes"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,28 +15,86 @@ import (
 
 // PracticeModel represents the single-player practice mode
 type PracticeModel struct {
-	quote        *quotes.Quote
-	typedInput   string
-	startTime    time.Time
-	endTime      time.Time
-	isFinished   bool
-	wpm          float64
-	accuracy     float64
-	correctChars int
-	totalChars   int
-	width        int
-	height       int
-	showResults  bool
+	quote       *quotes.Quote
+	typedInput  string
+	startTime   time.Time
+	endTime     time.Time
+	isFinished  bool
+	keystrokes  []scoring.Keystroke
+	score       scoring.Score
+	metric      scoring.Metric
+	width       int
+	height      int
+	showResults bool
+	source      quotes.Source
+	filter      quotes.Filter
+	normalizer  *quotes.Normalizer
+	history     *quotes.History
+	leaderboard *quotes.Leaderboard
+	ratings     *quotes.Ratings
+	layout      *keyboard.Layout
+	playerName  string
+	saveErr     error
+	voteErr     error
 }
 
+// maxRatingRedraws is how many quotes are drawn looking for one that
+// survives its rating before settling for the last
+const maxRatingRedraws = 10
+
 // NewPracticeModel creates a new practice mode model
 func NewPracticeModel() *PracticeModel {
+	return NewPracticeModelWithSource(quotes.DefaultSource(), quotes.Filter{})
+}
+
+// NewPracticeModelWithSource creates a practice model that draws quotes
+// matching filter from source
+func NewPracticeModelWithSource(source quotes.Source, filter quotes.Filter) *PracticeModel {
 	return &PracticeModel{
-		width:  80,
-		height: 24,
+		width:      80,
+		height:     24,
+		source:     source,
+		filter:     filter,
+		normalizer: quotes.DefaultNormalizer(),
+		layout:     keyboard.QWERTY,
+		metric:     scoring.DefaultMetric,
 	}
 }
 
+// SetNormalizer sets how quote text is cleaned up before it is typed
+func (m *PracticeModel) SetNormalizer(normalizer *quotes.Normalizer) {
+	m.normalizer = normalizer
+}
+
+// SetHistory sets where typed quotes are remembered so practice avoids
+// repeating them; nil disables the check
+func (m *PracticeModel) SetHistory(history *quotes.History) {
+	m.history = history
+}
+
+// SetLeaderboard sets where finished passages are recorded under
+// playerName so results can be compared with earlier runs
+func (m *PracticeModel) SetLeaderboard(leaderboard *quotes.Leaderboard, playerName string) {
+	m.leaderboard = leaderboard
+	m.playerName = playerName
+}
+
+// SetLayout sets the keyboard layout keys are remapped to
+func (m *PracticeModel) SetLayout(layout *keyboard.Layout) {
+	m.layout = layout
+}
+
+// SetMetric sets the speed figure shown as the headline
+func (m *PracticeModel) SetMetric(metric scoring.Metric) {
+	m.metric = metric
+}
+
+// SetRatings sets where votes on passages are kept; poorly rated quotes
+// come up less often. Nil disables voting.
+func (m *PracticeModel) SetRatings(ratings *quotes.Ratings) {
+	m.ratings = ratings
+}
+
 // Init initializes the practice model
 func (m *PracticeModel) Init() tea.Cmd {
 	return tea.Batch(
@@ -42,12 +103,25 @@ func (m *PracticeModel) Init() tea.Cmd {
 	)
 }
 
-// fetchQuote fetches a random quote
+// fetchQuote picks a random quote from the configured source, preferring
+// ones that have not been typed before and passing over poorly rated ones
 func (m *PracticeModel) fetchQuote() tea.Cmd {
+	source, filter, normalizer, history, ratings := m.source, m.filter, m.normalizer, m.history, m.ratings
 	return func() tea.Msg {
-		fetcher := quotes.NewFetcher()
-		quote := fetcher.FetchRandomQuoteWithFallback()
-		return QuoteMsg{Quote: quote}
+		var quote *quotes.Quote
+		for attempt := 0; attempt < maxRatingRedraws; attempt++ {
+			if history == nil {
+				quote = quotes.Pick(source, filter)
+			} else {
+				quote = history.Pick(source, filter, quotes.LocalPlayer)
+			}
+
+			// Texts and books are typed in order, whatever the rating
+			if _, sequential := source.(quotes.SequentialSource); sequential || ratings.Keep(quote) {
+				break
+			}
+		}
+		return QuoteMsg{Quote: normalizer.Quote(quote)}
 	}
 }
 
@@ -66,23 +140,39 @@ func (m *PracticeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			case "r", "enter":
 				// Restart practice
-				newModel := NewPracticeModel()
+				newModel := NewPracticeModelWithSource(m.source, m.filter)
+				newModel.normalizer = m.normalizer
+				newModel.history = m.history
+				newModel.leaderboard = m.leaderboard
+				newModel.ratings = m.ratings
+				newModel.layout = m.layout
+				newModel.metric = m.metric
+				newModel.playerName = m.playerName
 				newModel.width = m.width
 				newModel.height = m.height
 				return newModel, newModel.fetchQuote()
+			case "d":
+				// Cycle the difficulty of the next quote
+				m.filter.Difficulty = m.filter.Difficulty.Next()
+			}
+			if vote, ok := voteKeys[msg.String()]; ok && m.ratings != nil {
+				m.voteErr = m.ratings.Vote(m.quote, m.playerName, vote)
 			}
 		} else {
 			switch msg.String() {
 			case "ctrl+c", "esc":
 				return m, tea.Quit
 			case "backspace":
-				if len(m.typedInput) > 0 {
-					m.typedInput = m.typedInput[:len(m.typedInput)-1]
+				if typed, key, ok := game.PressBackspace(m.quote.Content, m.typedInput); ok {
+					m.typedInput = typed
+					m.keystrokes = append(m.keystrokes, key)
 					m.updateStats()
 				}
 			default:
-				if len(msg.String()) == 1 {
-					m.typedInput += msg.String()
+				if text, ok := typedText(msg, m.quote.Content, m.layout); ok {
+					typed, key := game.PressKey(m.quote.Content, m.typedInput, text)
+					m.typedInput = typed
+					m.keystrokes = append(m.keystrokes, key)
 					m.updateStats()
 
 					// Check if finished
@@ -96,6 +186,7 @@ func (m *PracticeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case QuoteMsg:
 		m.quote = msg.Quote
+		m.typedInput = game.SkipIndentation(m.quote.Content, "")
 		m.startTime = time.Now()
 		return m, nil
 	}
@@ -128,15 +219,17 @@ func (m *PracticeModel) renderPractice() string {
 	content.WriteString(InstructionStyle.Render("Type the text below as fast and accurately as possible"))
 	content.WriteString("\n\n")
 
-	// Quote author
+	// Quote author and difficulty
+	subtitle := fmt.Sprintf("Difficulty: %s", quotes.DifficultyOf(m.quote).Label())
 	if m.quote.Author != "" {
-		content.WriteString(SubtitleStyle.Render(fmt.Sprintf("— %s", m.quote.Author)))
-		content.WriteString("\n\n")
+		subtitle = fmt.Sprintf("— %s | %s", m.quote.Author, subtitle)
 	}
+	content.WriteString(SubtitleStyle.Render(subtitle))
+	content.WriteString("\n\n")
 
 	// Typing area
 	typingBox := MainBoxStyle.Width(m.width - 4).Render(
-		StyleTypingText(m.quote.Content, m.typedInput),
+		StyleTypingText(m.quote.Content, m.typedInput, typingWidth(m.width-4)),
 	)
 	content.WriteString(typingBox)
 	content.WriteString("\n\n")
@@ -147,10 +240,22 @@ func (m *PracticeModel) renderPractice() string {
 	content.WriteString("\n\n")
 
 	// Progress bar
-	progress := CreateProgressBar(len(m.typedInput), len(m.quote.Content), m.width-10)
+	progress := renderProgressBar(m.quote.Content, quotes.GraphemeCount(m.typedInput), m.width-10)
 	content.WriteString(ProgressBoxStyle.Render(progress))
 	content.WriteString("\n\n")
 
+	// Progress through a text or book
+	if sequential, ok := m.source.(quotes.SequentialSource); ok {
+		content.WriteString(m.renderDocumentProgress(sequential.Progress()))
+		content.WriteString("\n\n")
+	}
+
+	// Which key to press next on an emulated layout
+	if !m.layout.IsQWERTY() {
+		content.WriteString(renderKeyHint(m.layout, m.quote.Content, m.typedInput))
+		content.WriteString("\n\n")
+	}
+
 	// Instructions
 	content.WriteString(InstructionStyle.Render("Press Ctrl+C or Esc to quit"))
 
@@ -167,97 +272,108 @@ func (m *PracticeModel) renderResults() string {
 
 	// Results box
 	results := fmt.Sprintf(
-		"Words Per Minute: %s\nAccuracy: %s\nTime: %s\nCharacters: %d/%d",
-		FormatWPM(m.wpm),
-		FormatAccuracy(m.accuracy),
+		"%s: %s\nAccuracy: %s\nTime: %s\nCharacters: %d/%d\nDifficulty: %s (%.0f/100)\n\n%s",
+		m.metric.Label(),
+		m.metric.Format(m.score),
+		FormatAccuracy(m.score.Accuracy),
 		FormatDuration(m.endTime.Sub(m.startTime).Seconds()),
-		m.correctChars,
-		len(m.quote.Content),
+		m.score.CorrectChars,
+		quotes.GraphemeCount(m.quote.Content),
+		quotes.DifficultyOf(m.quote).Label(),
+		quotes.ScoreDifficulty(m.quote).Total,
+		formatScoreDetails(m.metric, m.score),
 	)
 
 	resultsBox := MainBoxStyle.Width(m.width - 4).Render(results)
 	content.WriteString(resultsBox)
 	content.WriteString("\n\n")
 
+	// Progress through a text or book, already moved on to the next passage
+	if sequential, ok := m.source.(quotes.SequentialSource); ok {
+		progress := sequential.Progress()
+		if progress.Passage == 0 && progress.Chapters > 1 {
+			content.WriteString(SuccessStyle.Render("Chapter complete!"))
+			content.WriteString("\n\n")
+		}
+		content.WriteString(m.renderDocumentProgress(progress))
+		content.WriteString("\n\n")
+	}
+
+	// All-time results for this passage
+	if m.leaderboard != nil && m.quote.Source != quotes.GeneratedSource {
+		stats, _ := m.leaderboard.Stats(m.quote.ID)
+		content.WriteString(renderQuoteRecords(stats, m.endTime.Sub(m.startTime).Seconds(), m.width-4))
+		content.WriteString("\n\n")
+	}
+
+	// Votes on this passage
+	if m.ratings != nil && m.quote.Source != quotes.GeneratedSource {
+		vote, _ := m.ratings.VoteOf(m.quote.ID, m.playerName)
+		content.WriteString(renderRating(m.ratings.Get(m.quote.ID), vote, m.voteErr))
+		content.WriteString("\n\n")
+	}
+
+	if m.saveErr != nil {
+		content.WriteString(ErrorStyle.Render(fmt.Sprintf("Could not save your progress: %v", m.saveErr)))
+		content.WriteString("\n\n")
+	}
+
 	// Instructions
-	content.WriteString(InstructionStyle.Render("Press 'r' to restart or 'q' to quit"))
+	content.WriteString(InstructionStyle.Render(fmt.Sprintf(
+		"Press 'r' to restart, 'd' to change difficulty (next: %s) or 'q' to quit",
+		m.filter.Difficulty.Label(),
+	)))
 
 	return content.String()
 }
 
-// renderStats renders the current stats
-func (m *PracticeModel) renderStats() string {
-	var stats strings.Builder
+// renderDocumentProgress renders how far through a text or book the
+// player is
+func (m *PracticeModel) renderDocumentProgress(progress quotes.DocumentProgress) string {
+	var content strings.Builder
 
-	// WPM
-	stats.WriteString(WPMTextStyle.Render(fmt.Sprintf("WPM: %s", FormatWPM(m.wpm))))
-	stats.WriteString("  ")
+	title := progress.Title
+	if progress.Chapters > 1 {
+		title += fmt.Sprintf(" | Chapter %d of %d", progress.ChapterIndex+1, progress.Chapters)
+		if progress.Chapter != "" {
+			title += ": " + progress.Chapter
+		}
+	}
+	content.WriteString(PlayerNameStyle.Render(title))
+	content.WriteString("\n")
 
-	// Accuracy
-	stats.WriteString(AccuracyTextStyle.Render(fmt.Sprintf("Accuracy: %s", FormatAccuracy(m.accuracy))))
-	stats.WriteString("  ")
+	content.WriteString(InstructionStyle.Render(fmt.Sprintf(
+		"Passage %d of %d | %.1f%% of the whole text",
+		progress.Passage+1, progress.Passages, progress.Percent(),
+	)))
+	content.WriteString("\n")
 
-	// Time
-	elapsed := time.Since(m.startTime).Seconds()
-	stats.WriteString(TimeTextStyle.Render(fmt.Sprintf("Time: %s", FormatDuration(elapsed))))
+	content.WriteString(CreateProgressBar(progress.Done, progress.Total, m.width-14))
 
-	return StatsBoxStyle.Render(stats.String())
+	return StatsBoxStyle.Render(content.String())
 }
 
-// updateStats updates the player's statistics
-func (m *PracticeModel) updateStats() {
-	if m.quote == nil {
-		return
-	}
-
-	// Calculate accuracy
-	m.calculateAccuracy()
-
-	// Calculate WPM
-	m.calculateWPM()
+// renderStats renders the current stats
+func (m *PracticeModel) renderStats() string {
+	return renderScoreStats(m.metric, m.score, time.Since(m.startTime))
 }
 
-// calculateAccuracy calculates typing accuracy
-func (m *PracticeModel) calculateAccuracy() {
-	if len(m.quote.Content) == 0 {
-		m.accuracy = 0.0
+// updateStats scores the typing so far, or up to the end once finished
+func (m *PracticeModel) updateStats() {
+	if m.quote == nil {
 		return
 	}
 
-	correct := 0
-	total := len(m.typedInput)
-	if total > len(m.quote.Content) {
-		total = len(m.quote.Content)
-	}
-
-	for i := 0; i < total; i++ {
-		if i < len(m.typedInput) && i < len(m.quote.Content) && m.typedInput[i] == m.quote.Content[i] {
-			correct++
-		}
-	}
-
-	m.correctChars = correct
-	m.totalChars = total
-
-	if total > 0 {
-		m.accuracy = float64(correct) / float64(total) * 100.0
-	} else {
-		m.accuracy = 0.0
-	}
-}
-
-// calculateWPM calculates words per minute
-func (m *PracticeModel) calculateWPM() {
-	elapsed := time.Since(m.startTime).Minutes()
-	if elapsed > 0 {
-		// WPM = (correct characters / 5) / minutes
-		m.wpm = float64(m.correctChars) / 5.0 / elapsed
+	end := time.Now()
+	if m.isFinished {
+		end = m.endTime
 	}
+	m.score = scoring.Compute(m.quote.Content, m.typedInput, m.keystrokes, m.startTime, end)
 }
 
 // isComplete checks if the typing is complete
 func (m *PracticeModel) isComplete() bool {
-	return len(m.typedInput) >= len(m.quote.Content)
+	return quotes.GraphemeCount(m.typedInput) >= quotes.GraphemeCount(m.quote.Content)
 }
 
 // finish marks the practice as finished
@@ -265,6 +381,25 @@ func (m *PracticeModel) finish() {
 	m.isFinished = true
 	m.endTime = time.Now()
 	m.showResults = true
+	m.updateStats()
+
+	// Move documents on to the next passage
+	if sequential, ok := m.source.(quotes.SequentialSource); ok {
+		m.saveErr = sequential.Advance()
+	} else if m.history != nil {
+		m.saveErr = m.history.Record(quotes.LocalPlayer, m.quote)
+	}
+
+	if m.leaderboard != nil && m.saveErr == nil {
+		m.saveErr = m.leaderboard.Record(m.quote, quotes.Result{
+			Player:   m.playerName,
+			WPM:      m.score.NetWPM,
+			Accuracy: m.score.Accuracy,
+			Seconds:  m.endTime.Sub(m.startTime).Seconds(),
+			Mode:     quotes.ModePractice,
+			At:       m.endTime,
+		})
+	}
 }
 
 // QuoteMsg represents a message containing a quote
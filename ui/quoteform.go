@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Quote form fields
+const (
+	fieldContent = iota
+	fieldAuthor
+	fieldTags
+	fieldCount
+)
+
+var fieldLabels = [fieldCount]string{"Quote", "Author", "Tags (comma-separated)"}
+
+// quoteForm edits the text, author and tags of a quote
+type quoteForm struct {
+	fields [fieldCount][]rune
+	focus  int
+}
+
+// newQuoteForm creates a form filled with the given values
+func newQuoteForm(content, author string, tags []string) *quoteForm {
+	form := &quoteForm{}
+	form.fields[fieldContent] = []rune(content)
+	form.fields[fieldAuthor] = []rune(author)
+	form.fields[fieldTags] = []rune(strings.Join(tags, ", "))
+	return form
+}
+
+// update applies a key press and reports whether the form was submitted
+func (f *quoteForm) update(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "ctrl+s":
+		return true
+	case "enter":
+		if f.focus == fieldCount-1 {
+			return true
+		}
+		f.focus++
+	case "tab", "down":
+		f.focus = (f.focus + 1) % fieldCount
+	case "shift+tab", "up":
+		f.focus = (f.focus + fieldCount - 1) % fieldCount
+	case "backspace":
+		if field := f.fields[f.focus]; len(field) > 0 {
+			f.fields[f.focus] = field[:len(field)-1]
+		}
+	case "ctrl+u":
+		f.fields[f.focus] = nil
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			f.fields[f.focus] = append(f.fields[f.focus], msg.Runes...)
+		}
+	}
+	return false
+}
+
+// values returns the entered quote, author and tags
+func (f *quoteForm) values() (string, string, []string) {
+	return string(f.fields[fieldContent]), string(f.fields[fieldAuthor]), strings.Split(string(f.fields[fieldTags]), ",")
+}
+
+// view renders the form fields, marking the focused one
+func (f *quoteForm) view(width int) string {
+	var content strings.Builder
+
+	for i, label := range fieldLabels {
+		value := string(f.fields[i])
+		if i == f.focus {
+			content.WriteString(PlayerNameStyle.Render("> " + label))
+			value += "█"
+		} else {
+			content.WriteString(InstructionStyle.UnsetMargins().Render("  " + label))
+		}
+		content.WriteString("\n")
+		content.WriteString(UntypedTextStyle.Render(value))
+		content.WriteString("\n\n")
+	}
+
+	return MainBoxStyle.Width(width - 4).Render(strings.TrimRight(content.String(), "\n"))
+}
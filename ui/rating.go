@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"typeracer-tui/quotes"
+)
+
+// voteKeys maps results screen keys to votes on the passage
+var voteKeys = map[string]quotes.Vote{
+	"+": quotes.VoteUp,
+	"=": quotes.VoteUp,
+	"-": quotes.VoteDown,
+	"f": quotes.VoteFlag,
+}
+
+// voteLabels describes each vote to the player who cast it
+var voteLabels = map[quotes.Vote]string{
+	quotes.VoteUp:   "You liked this passage",
+	quotes.VoteDown: "You disliked this passage",
+	quotes.VoteFlag: "You flagged this passage as broken",
+}
+
+// renderRating renders the votes on a passage and how to cast one
+func renderRating(rating quotes.Rating, vote quotes.Vote, err error) string {
+	var content strings.Builder
+
+	content.WriteString(LeaderboardWPMStyle.Render(fmt.Sprintf(
+		"Rating: %d up | %d down | %d flagged", rating.Up, rating.Down, rating.Flags,
+	)))
+	content.WriteString("\n")
+
+	if label, voted := voteLabels[vote]; voted {
+		content.WriteString(SuccessStyle.UnsetMargins().Render(label))
+		content.WriteString("\n")
+	}
+	if err != nil {
+		content.WriteString(ErrorStyle.UnsetMargins().Render(fmt.Sprintf("Could not save your vote: %v", err)))
+		content.WriteString("\n")
+	}
+
+	content.WriteString(InstructionStyle.UnsetMargins().Render(
+		"Rate this passage: '+' like, '-' dislike, 'f' flag as broken (bad punctuation, cut-off text)",
+	))
+	return content.String()
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"typeracer-tui/quotes"
+)
+
+// renderQuoteRecords renders how a time compares to every recorded run of
+// the same quote, along with the fastest runs. A non-positive yourSeconds
+// leaves out the comparison.
+func renderQuoteRecords(stats quotes.QuoteStats, yourSeconds float64, width int) string {
+	var content strings.Builder
+
+	content.WriteString(LeaderboardTitleStyle.Render("This Passage"))
+	content.WriteString("\n")
+
+	if stats.Races == 0 {
+		content.WriteString(InstructionStyle.Render("Nobody has finished this passage yet."))
+		return MainBoxStyle.Width(width).Render(content.String())
+	}
+
+	content.WriteString(LeaderboardWPMStyle.Render(fmt.Sprintf(
+		"Typed %d times | Average: %s", stats.Races, FormatWPM(stats.AverageWPM()),
+	)))
+	content.WriteString("\n")
+
+	if yourSeconds > 0 {
+		content.WriteString(LeaderboardTimeStyle.Render(fmt.Sprintf(
+			"Your time %s ranks #%d of %d (faster than or equal to %.0f%%)",
+			FormatDuration(yourSeconds), stats.Rank(yourSeconds), len(stats.Times), stats.Percentile(yourSeconds),
+		)))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	for i, result := range stats.Top {
+		if i == 3 {
+			break
+		}
+		content.WriteString(LeaderboardEntryStyle.Render(fmt.Sprintf(
+			"%d. %s  %s  %s", i+1, result.Player, FormatDuration(result.Seconds), FormatWPM(result.WPM),
+		)))
+		content.WriteString("\n")
+	}
+
+	return MainBoxStyle.Width(width).Render(content.String())
+}
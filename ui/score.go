@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"typeracer-tui/game/scoring"
+)
+
+// renderScoreStats renders the live headline metric, accuracy and time
+func renderScoreStats(metric scoring.Metric, score scoring.Score, elapsed time.Duration) string {
+	var stats strings.Builder
+
+	// Headline speed
+	stats.WriteString(WPMTextStyle.Render(fmt.Sprintf("%s: %s", metric.Label(), metric.Format(score))))
+	stats.WriteString("  ")
+
+	// Accuracy
+	stats.WriteString(AccuracyTextStyle.Render(fmt.Sprintf("Accuracy: %s", FormatAccuracy(score.Accuracy))))
+	stats.WriteString("  ")
+
+	// Time
+	stats.WriteString(TimeTextStyle.Render(fmt.Sprintf("Time: %s", FormatDuration(elapsed.Seconds()))))
+
+	return StatsBoxStyle.Render(stats.String())
+}
+
+// formatScoreDetails lists the metrics besides the headline, the errors
+// and the consistency of a finished run, one per line
+func formatScoreDetails(metric scoring.Metric, score scoring.Score) string {
+	var lines []string
+	for other := metric.Next(); other != metric; other = other.Next() {
+		lines = append(lines, fmt.Sprintf("%s: %s", other.Label(), other.Format(score)))
+	}
+	lines = append(lines,
+		fmt.Sprintf("Errors: %d corrected, %d uncorrected", score.CorrectedErrors, score.UncorrectedErrors),
+		fmt.Sprintf("Consistency: %.0f%%", score.Consistency),
+	)
+	return strings.Join(lines, "\n")
+}
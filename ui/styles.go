@@ -2,6 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+
+	"typeracer-tui/quotes"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -194,51 +197,81 @@ var (
 				Foreground(Orange)
 )
 
-// Helper functions for styling text with typing progress
-func StyleTypingText(prompt, typed string) string {
-	if len(typed) == 0 {
+// Helper functions for styling text with typing progress. The prompt and
+// typed text are compared one grapheme cluster at a time. Left-to-right text
+// is left for the enclosing box to wrap; text with right-to-left script is
+// wrapped to width and reordered for display here.
+func StyleTypingText(prompt, typed string, width int) string {
+	chars := quotes.Graphemes(prompt)
+	rtl := hasRTL(chars)
+	if len(typed) == 0 && !rtl && !strings.ContainsAny(prompt, "\n\t") {
 		return UntypedTextStyle.Render(prompt)
 	}
 
-	var result string
-	for i, char := range prompt {
-		if i < len(typed) {
-			if rune(typed[i]) == char {
-				result += CorrectTextStyle.Render(string(char))
-			} else {
-				result += IncorrectTextStyle.Render(string(char))
+	// Renders the prompt character at index i; right-to-left text may show a
+	// mirrored glyph in place of the character itself
+	typedChars := quotes.Graphemes(typed)
+	render := func(i int, glyph string) string {
+		if i < len(typedChars) {
+			if typedChars[i] == chars[i] {
+				return styleTypingChar(CorrectTextStyle, glyph, false)
 			}
-		} else if i == len(typed) {
-			result += CurrentTextStyle.Render(string(char))
-		} else {
-			result += UntypedTextStyle.Render(string(char))
+			return styleTypingChar(IncorrectTextStyle, glyph, true)
+		} else if i == len(typedChars) {
+			return styleTypingChar(CurrentTextStyle, glyph, true)
 		}
+		return styleTypingChar(UntypedTextStyle, glyph, false)
 	}
 
-	return result
+	if rtl {
+		return layoutBidi(chars, render, width)
+	}
+	var result strings.Builder
+	for i, char := range chars {
+		result.WriteString(render(i, char))
+	}
+	return result.String()
+}
+
+// styleTypingChar renders one prompt character. Tabs become spaces, and a
+// newline shows a return marker when it is the cursor or was mistyped so
+// the line break stays visible.
+func styleTypingChar(style lipgloss.Style, char string, highlight bool) string {
+	switch char {
+	case "\n":
+		if highlight {
+			return style.Render("↵") + "\n"
+		}
+		return "\n"
+	case "\t":
+		return style.Render("    ")
+	default:
+		return style.Render(char)
+	}
 }
 
 // Create a progress bar
 func CreateProgressBar(current, total int, width int) string {
-	if total == 0 {
-		return ProgressBarEmptyStyle.Render(string(make([]rune, width)))
-	}
+	filled, empty := progressBarParts(current, total, width)
+	return ProgressBarStyle.Render(filled) + ProgressBarEmptyStyle.Render(empty)
+}
 
-	filled := int(float64(current) / float64(total) * float64(width))
-	if filled > width {
-		filled = width
-	}
+// CreateProgressBarRTL creates a progress bar that fills from the right, for
+// right-to-left text
+func CreateProgressBarRTL(current, total int, width int) string {
+	filled, empty := progressBarParts(current, total, width)
+	return ProgressBarEmptyStyle.Render(empty) + ProgressBarStyle.Render(filled)
+}
 
-	bar := ""
-	for i := 0; i < width; i++ {
-		if i < filled {
-			bar += "█"
-		} else {
-			bar += "░"
-		}
+// progressBarParts returns the filled and empty parts of a progress bar
+func progressBarParts(current, total int, width int) (string, string) {
+	width = max(width, 0)
+	if total == 0 {
+		return "", strings.Repeat("░", width)
 	}
 
-	return ProgressBarStyle.Render(bar[:filled]) + ProgressBarEmptyStyle.Render(bar[filled:])
+	filled := min(int(float64(current)/float64(total)*float64(width)), width)
+	return strings.Repeat("█", filled), strings.Repeat("░", width-filled)
 }
 
 // Create a racer indicator
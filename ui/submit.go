@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+
+	"typeracer-tui/game"
+	"typeracer-tui/quotes"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var errSubmissionsDisabled = errors.New("quote submissions are turned off on this server")
+
+// SubmitModel lets a player propose a new quote for review
+type SubmitModel struct {
+	manager    *game.Manager
+	playerName string
+	language   quotes.Language
+	lobby      tea.Model
+	form       *quoteForm
+	message    string
+	err        error
+	width      int
+	height     int
+}
+
+// NewSubmitModel creates a submission form for quotes in the player's
+// language that returns to lobby when closed
+func NewSubmitModel(manager *game.Manager, playerName string, language quotes.Language, lobby tea.Model, width, height int) *SubmitModel {
+	return &SubmitModel{
+		manager:    manager,
+		playerName: playerName,
+		language:   language,
+		lobby:      lobby,
+		form:       newQuoteForm("", "", nil),
+		width:      width,
+		height:     height,
+	}
+}
+
+// Init initializes the submission form
+func (m *SubmitModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m *SubmitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.lobby.Update(msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return m.lobby, nil
+		}
+
+		m.message = ""
+		if m.form.update(msg) {
+			m.submit()
+		}
+		return m, nil
+
+	case StartGameMsg:
+		// The race starts without waiting for the form
+		return m.lobby.Update(msg)
+
+	case RefreshLobbyMsg:
+		// Keep the lobby up to date underneath the form
+		_, cmd := m.lobby.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// submit sends the form to the review queue
+func (m *SubmitModel) submit() {
+	submissions := m.manager.Submissions()
+	if submissions == nil {
+		m.err = errSubmissionsDisabled
+		return
+	}
+
+	content, author, tags := m.form.values()
+	if _, m.err = submissions.Submit(content, author, tags, m.language, m.playerName); m.err != nil {
+		return
+	}
+	m.form = newQuoteForm("", "", nil)
+	m.message = "Thanks! Your quote will be raced once a moderator approves it."
+}
+
+// View renders the submission form
+func (m *SubmitModel) View() string {
+	var content strings.Builder
+
+	content.WriteString(TitleStyle.Render("Submit a Quote in " + m.language.Label()))
+	content.WriteString("\n\n")
+	content.WriteString(m.form.view(m.width))
+	content.WriteString("\n")
+
+	if m.err != nil {
+		content.WriteString(ErrorStyle.Render(m.err.Error()))
+		content.WriteString("\n")
+	} else if m.message != "" {
+		content.WriteString(SuccessStyle.Render(m.message))
+		content.WriteString("\n")
+	}
+
+	content.WriteString(InstructionStyle.Render("Tab to switch fields, Enter on tags or Ctrl+S to submit, Esc to go back"))
+
+	return content.String()
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"strings"
+
+	"typeracer-tui/keyboard"
+	"typeracer-tui/quotes"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typedText returns the text a key press adds to the input, remapped from
+// the physical QWERTY keyboard to the player's layout. A key may produce
+// several runes, as with dead keys, input methods and emoji, but pasted text
+// is refused. Enter and Tab only count when the prompt contains newlines or
+// tabs, as in code snippets.
+func typedText(msg tea.KeyMsg, prompt string, layout *keyboard.Layout) (string, bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		return "\n", strings.Contains(prompt, "\n")
+	case tea.KeyTab:
+		return "\t", strings.Contains(prompt, "\t")
+	case tea.KeySpace:
+		return " ", true
+	case tea.KeyRunes:
+		if msg.Alt || msg.Paste || len(msg.Runes) == 0 {
+			return "", false
+		}
+		return layout.Remap(string(msg.Runes)), true
+	default:
+		return "", false
+	}
+}
+
+// renderKeyHint renders which key and finger type the next character of
+// the prompt on the player's layout
+func renderKeyHint(layout *keyboard.Layout, prompt, typed string) string {
+	if len(typed) >= len(prompt) {
+		return ""
+	}
+	if !strings.HasPrefix(prompt, typed) {
+		return InstructionStyle.Render("Next: Backspace to fix the mistake")
+	}
+
+	label := quotes.FirstGrapheme(prompt[len(typed):])
+	hint, ok := layout.HintFor([]rune(label)[0])
+	if !ok {
+		return ""
+	}
+
+	switch label {
+	case " ":
+		label = "space"
+	case "\n":
+		label = "new line"
+	case "\t":
+		label = "tab"
+	}
+	return InstructionStyle.Render("Next: " + label + " — " + hint.String())
+}
+
+// renderProgressBar shows how much of the prompt has been typed, filling
+// from the right when the prompt reads right to left
+func renderProgressBar(prompt string, typed, width int) string {
+	if isRTL(prompt) {
+		return CreateProgressBarRTL(typed, quotes.GraphemeCount(prompt), width)
+	}
+	return CreateProgressBar(typed, quotes.GraphemeCount(prompt), width)
+}
+
+// typingWidth returns the columns available for the prompt in a MainBoxStyle
+// box of the given width
+func typingWidth(boxWidth int) int {
+	return boxWidth - MainBoxStyle.GetHorizontalPadding()
+}